@@ -14,6 +14,7 @@ import (
 )
 
 const timeScale = 1e+12
+const speedUp = 1e+4 // simulated seconds per wall-clock second
 const wsize = 600
 const wradius = wsize / 2
 
@@ -45,17 +46,22 @@ func main() {
 			"bodies: %2d\tscale: %f         \r",
 			count, -math.Log10(spaceScale),
 		)
-		wait(system)
+		if err := wait(system); err != nil {
+			fmt.Printf("\nsimulation stopped: %v\n", err)
+			break
+		}
 	}
 }
 
-func wait(system gravity.System) {
+func wait(system gravity.System) error {
 	sdl.Delay(100)
-	now := float64(time.Now().UnixNano()) * 10e-6
-	if tick != 0 {
-		system.Step(now - tick)
-	}
+	now := float64(time.Now().UnixNano()) / 1e+9
+	last := tick
 	tick = now
+	if last == 0 {
+		return nil
+	}
+	return system.Step((now - last) * speedUp)
 }
 
 func plotSystem(surface *sdl.Surface, system gravity.System) {
@@ -63,7 +69,7 @@ func plotSystem(surface *sdl.Surface, system gravity.System) {
 		&sdl.Rect{X: 0, Y: 0, W: wsize, H: wsize},
 		0x00002255,
 	)
-	center := system.GetBody("Sun").GetPosition()
+	center := system.CenterOfMass()
 	bodies := system.GetBodies()
 
 	var futher float64
@@ -139,16 +145,20 @@ func initializeSDL(width, height int) *sdl.Window {
 }
 
 func initializeSystem() gravity.System {
-	body, _ := gravity.NewBody("Sun", 2e+30, 0, 0, 0)
-	system, _ := gravity.NewSystem(body)
+	sun, _ := gravity.NewBody("Sun", 2e+30, 0, 0, 0)
+	// leapfrog takes the softening close encounters between the randomly
+	// placed planets need
+	system, _ := gravity.NewSystemWithIntegrator(gravity.NewLeapfrog(), sun)
+	system.SetSoftening(1e+7)
+	system.SetAutoRecenter(true)
+	system.SetEscapePolicy(gravity.EscapePolicy{Radius: 1e+10, Remove: true})
 
 	for i := 1; i <= 10; i++ {
 		mass := 1.3e+22 + rand.Float64()*2e+27
 		x := rand.Float64()*4.5e+9 - 2.25e+9
 		y := rand.Float64()*4.5e+9 - 2.25e+9
-		body, _ = gravity.NewBody(fmt.Sprintf("Planet %v", i), mass, x, y, 0)
-		inertia := body.GetPosition().TanXY().Mul(5e+21)
-		body.SetInertia(inertia)
+		body, _ := gravity.NewBody(fmt.Sprintf("Planet %v", i), mass, x, y, 0)
+		system.SetCircularOrbit(body, sun, gravity.NewPoint(0, 0, 1))
 		system.AddBody(body)
 	}
 
@@ -0,0 +1,301 @@
+package gravity
+
+import (
+	"fmt"
+	"math"
+)
+
+// AdaptiveIntegrator is an Integrator able to estimate its local error. The
+// estimate is relative to the system scale: position errors are divided by
+// the largest distance from the origin, and velocity errors by the largest
+// speed.
+type AdaptiveIntegrator interface {
+	Integrator
+	IntegrateWithError(bodies []Body, t, dt float64, accel AccelFunc) (float64, error)
+	Order() int
+}
+
+// AdvanceReport describes the internal steps taken by System.Advance
+type AdvanceReport struct {
+	Steps    []float64 // accepted step sizes
+	Errors   []float64 // estimated error of each accepted step
+	Rejected int       // steps retried with a smaller size
+}
+
+type tableau struct {
+	c, b, e []float64 // e holds the error weights, b - b*
+	a       [][]float64
+	order   int
+}
+
+type embeddedRK struct {
+	tableau
+}
+
+type stepDoubling struct {
+	integrator Integrator
+}
+
+type snapshot struct {
+	positions, velocities []Point
+}
+
+var dormandPrince = tableau{
+	c: []float64{0, 1.0 / 5, 3.0 / 10, 4.0 / 5, 8.0 / 9, 1, 1},
+	a: [][]float64{
+		{},
+		{1.0 / 5},
+		{3.0 / 40, 9.0 / 40},
+		{44.0 / 45, -56.0 / 15, 32.0 / 9},
+		{19372.0 / 6561, -25360.0 / 2187, 64448.0 / 6561, -212.0 / 729},
+		{9017.0 / 3168, -355.0 / 33, 46732.0 / 5247, 49.0 / 176, -5103.0 / 18656},
+		{35.0 / 384, 0, 500.0 / 1113, 125.0 / 192, -2187.0 / 6784, 11.0 / 84},
+	},
+	b: []float64{35.0 / 384, 0, 500.0 / 1113, 125.0 / 192, -2187.0 / 6784, 11.0 / 84, 0},
+	e: []float64{
+		35.0/384 - 5179.0/57600,
+		0,
+		500.0/1113 - 7571.0/16695,
+		125.0/192 - 393.0/640,
+		-2187.0/6784 + 92097.0/339200,
+		11.0/84 - 187.0/2100,
+		-1.0 / 40,
+	},
+	order: 4,
+}
+
+// NewDormandPrince creates the embedded Runge-Kutta 5(4) integrator by
+// Dormand and Prince
+func NewDormandPrince() AdaptiveIntegrator {
+	return embeddedRK{dormandPrince}
+}
+
+// NewStepDoubling makes any integrator adaptive, estimating its error by
+// comparing a full step against two half steps. The integrator order is not
+// known, so the error is conservatively taken as first order.
+func NewStepDoubling(integrator Integrator) AdaptiveIntegrator {
+	return stepDoubling{integrator}
+}
+
+func (rk embeddedRK) Order() int {
+	return rk.order
+}
+
+func (rk embeddedRK) Integrate(bodies []Body, t, dt float64, accel AccelFunc) error {
+	_, err := rk.IntegrateWithError(bodies, t, dt, accel)
+	return err
+}
+
+func (rk embeddedRK) IntegrateWithError(bodies []Body, t, dt float64, accel AccelFunc) (float64, error) {
+	length := len(bodies)
+	state := takeSnapshot(bodies)
+	v0 := state.velocities
+	stages := len(rk.c)
+	kx := make([][]Point, stages)
+	kv := make([][]Point, stages)
+
+	for i := 0; i < stages; i++ {
+		dx := combine(length, rk.a[i], kx)
+		dv := combine(length, rk.a[i], kv)
+		setState(bodies, state.positions, v0, dx, dv, dt)
+		kx[i] = velocities(bodies)
+		kv[i] = accel(bodies, t+rk.c[i]*dt)
+	}
+
+	setState(bodies, state.positions, v0, combine(length, rk.b, kx), combine(length, rk.b, kv), dt)
+
+	errX := combine(length, rk.e, kx)
+	errV := combine(length, rk.e, kv)
+	for i := range errX {
+		errX[i] = errX[i].Mul(dt)
+		errV[i] = errV[i].Mul(dt)
+	}
+	return scaledError(bodies, errX, errV), nil
+}
+
+func (sd stepDoubling) Order() int {
+	return 1
+}
+
+func (sd stepDoubling) Integrate(bodies []Body, t, dt float64, accel AccelFunc) error {
+	return sd.integrator.Integrate(bodies, t, dt, accel)
+}
+
+func (sd stepDoubling) IntegrateWithError(bodies []Body, t, dt float64, accel AccelFunc) (float64, error) {
+	state := takeSnapshot(bodies)
+	if err := sd.integrator.Integrate(bodies, t, dt, accel); err != nil {
+		return 0, err
+	}
+	full := takeSnapshot(bodies)
+
+	state.restore(bodies)
+	if err := sd.integrator.Integrate(bodies, t, dt/2, accel); err != nil {
+		return 0, err
+	}
+	if err := sd.integrator.Integrate(bodies, t+dt/2, dt/2, accel); err != nil {
+		return 0, err
+	}
+
+	errX := make([]Point, len(bodies))
+	errV := make([]Point, len(bodies))
+	for i, b := range bodies {
+		errX[i] = b.GetPosition().Diff(full.positions[i])
+		errV[i] = b.GetVelocity().Diff(full.velocities[i])
+	}
+	return scaledError(bodies, errX, errV), nil
+}
+
+// Advance integrates up to the target time, picking internal step sizes
+// that keep the estimated error of each step within tolerance. It uses the
+// system integrator when it is adaptive, or wraps it with NewStepDoubling.
+// Steps are plain float64 sums, so it refuses extended precisions.
+func (s *system) Advance(target, tolerance float64) (AdvanceReport, error) {
+	var report AdvanceReport
+
+	if s.status != nil {
+		return report, s.status
+	}
+
+	if target < s.time || math.IsNaN(target) || math.IsInf(target, 0) {
+		s.status = fmt.Errorf("invalid target time %v", target)
+		return report, s.status
+	}
+
+	if tolerance <= 0 || math.IsNaN(tolerance) || math.IsInf(tolerance, 0) {
+		s.status = fmt.Errorf("invalid tolerance %v", tolerance)
+		return report, s.status
+	}
+
+	unitIntegrator, err := s.unitIntegrator(s.integrator)
+	if err != nil {
+		return report, err
+	}
+	if err := s.checkPrecision(true); err != nil {
+		return report, err
+	}
+
+	if s.baseline == nil {
+		s.ResetBaseline()
+	}
+
+	integrator, ok := unitIntegrator.(AdaptiveIntegrator)
+	if !ok {
+		integrator = NewStepDoubling(unitIntegrator)
+	}
+	exponent := 1 / float64(integrator.Order()+1)
+
+	dt := s.adaptiveDt
+	if dt == 0 {
+		dt = target - s.time
+	}
+
+	for s.time < target {
+		if err := s.resolveCollisions(); err != nil {
+			s.status = err
+			return report, err
+		}
+		s.detectEscapes()
+		if err := s.dispatchEvents(); err != nil {
+			return report, err
+		}
+
+		h := math.Min(dt, target-s.time)
+		if s.time+h == s.time {
+			s.status = fmt.Errorf("step size underflow at time %v", s.time)
+			return report, s.status
+		}
+
+		bodies := s.bodyList()
+		state := takeSnapshot(bodies)
+		estimate, err := integrator.IntegrateWithError(bodies, s.time, h, s.accelerations)
+		if err != nil {
+			s.status = err
+			return report, err
+		}
+		if math.IsNaN(estimate) || math.IsInf(estimate, 0) {
+			state.restore(bodies)
+			s.status = fmt.Errorf("non-finite error estimate %v at time %v", estimate, s.time)
+			return report, s.status
+		}
+
+		factor := 5.0
+		if estimate > 0 {
+			factor = math.Max(0.2, math.Min(5, 0.9*math.Pow(tolerance/estimate, exponent)))
+		}
+
+		if estimate <= tolerance {
+			s.time += h
+			report.Steps = append(report.Steps, h)
+			report.Errors = append(report.Errors, estimate)
+			if h == dt {
+				dt *= factor
+			}
+		} else {
+			state.restore(bodies)
+			report.Rejected++
+			dt = h * factor
+		}
+	}
+
+	s.adaptiveDt = dt
+	if err := s.resolveCollisions(); err != nil {
+		s.status = err
+		return report, err
+	}
+	s.detectEscapes()
+	return report, s.dispatchEvents()
+}
+
+func takeSnapshot(bodies []Body) snapshot {
+	state := snapshot{
+		positions:  make([]Point, len(bodies)),
+		velocities: make([]Point, len(bodies)),
+	}
+	for i, b := range bodies {
+		state.positions[i] = b.GetPosition()
+		state.velocities[i] = b.GetVelocity()
+	}
+	return state
+}
+
+func (state snapshot) restore(bodies []Body) {
+	for i, b := range bodies {
+		b.SetPosition(state.positions[i])
+		b.SetVelocity(state.velocities[i])
+	}
+}
+
+// combine returns Σ weights[j]·k[j] for each body
+func combine(length int, weights []float64, k [][]Point) []Point {
+	res := make([]Point, length)
+	for i := range res {
+		sum := NewPoint(0, 0, 0)
+		for j, w := range weights {
+			if w != 0 {
+				sum = sum.Add(k[j][i].Mul(w))
+			}
+		}
+		res[i] = sum
+	}
+	return res
+}
+
+// scaledError divides errors by the system extent and its top speed
+func scaledError(bodies []Body, errX, errV []Point) float64 {
+	var extent, speed float64
+	for _, b := range bodies {
+		extent = math.Max(extent, b.GetPosition().Magnitude())
+		speed = math.Max(speed, b.GetVelocity().Magnitude())
+	}
+
+	var res float64
+	for i := range bodies {
+		if extent > 0 {
+			res = math.Max(res, errX[i].Magnitude()/extent)
+		}
+		if speed > 0 {
+			res = math.Max(res, errV[i].Magnitude()/speed)
+		}
+	}
+	return res
+}
@@ -0,0 +1,200 @@
+package gravity
+
+import (
+	"fmt"
+	"math"
+)
+
+// maxTreeDepth bounds octree subdivision, so coincident bodies share a leaf
+const maxTreeDepth = 64
+
+type barnesHut struct {
+	theta   float64
+	workers int
+}
+
+type octree struct {
+	center   Point
+	size     float64 // half the cell width
+	mass     float64
+	charge   float64
+	moment   Point // Σ m·x, the centre of mass times the total mass
+	split    bool
+	bodies   []Body
+	children [8]*octree
+}
+
+// NewBarnesHutSolver creates an O(N log N) octree solver: cells seen under
+// an angle smaller than theta are taken as a single mass at their centre;
+// bodies walk the tree on one worker per CPU
+func NewBarnesHutSolver(theta float64) (Solver, error) {
+	if theta < 0 || math.IsNaN(theta) || math.IsInf(theta, 0) {
+		return nil, fmt.Errorf("invalid opening angle: %v", theta)
+	}
+
+	return barnesHut{theta: theta}, nil
+}
+
+// NewBarnesHutSolverWithWorkers creates the octree solver running on at
+// most the given number of workers; a single worker computes serially
+func NewBarnesHutSolverWithWorkers(theta float64, workers int) (Solver, error) {
+	if workers < 1 {
+		return nil, fmt.Errorf("invalid worker count: %v", workers)
+	}
+
+	solver, err := NewBarnesHutSolver(theta)
+	if err != nil {
+		return nil, err
+	}
+	res := solver.(barnesHut)
+	res.workers = workers
+	return res, nil
+}
+
+func (s barnesHut) Accelerations(bodies []Body, grav GravFunc) []Point {
+	res := make([]Point, len(bodies))
+	massive, _ := partitionMassive(bodies)
+	if len(massive) == 0 {
+		for i := range res {
+			res[i] = NewPoint(0, 0, 0)
+		}
+		return res
+	}
+
+	sources := make([]Body, len(massive))
+	for k, i := range massive {
+		sources[k] = bodies[i]
+	}
+
+	root := newOctree(sources)
+	for _, b := range sources {
+		root.insert(b, 0)
+	}
+
+	// the tree is read only from here on, so bodies walk it concurrently
+	parallelFor(len(bodies), workerCount(s.workers, len(bodies)), func(_, i int) {
+		if b := bodies[i]; b.GetMass() == 0 {
+			res[i] = root.force(unitMass(b), s.theta, grav)
+		} else {
+			res[i] = root.force(b, s.theta, grav).Mul(1 / b.GetMass())
+		}
+	})
+	return res
+}
+
+func newOctree(bodies []Body) *octree {
+	low := bodies[0].GetPosition()
+	high := low
+	for _, b := range bodies[1:] {
+		pos := b.GetPosition()
+		low = NewPoint(
+			math.Min(low.GetX(), pos.GetX()),
+			math.Min(low.GetY(), pos.GetY()),
+			math.Min(low.GetZ(), pos.GetZ()),
+		)
+		high = NewPoint(
+			math.Max(high.GetX(), pos.GetX()),
+			math.Max(high.GetY(), pos.GetY()),
+			math.Max(high.GetZ(), pos.GetZ()),
+		)
+	}
+
+	diag := high.Diff(low)
+	size := math.Max(diag.GetX(), math.Max(diag.GetY(), diag.GetZ())) / 2
+	if size == 0 {
+		size = 1
+	}
+
+	return &octree{
+		center: low.Add(high).Mul(0.5),
+		size:   size * (1 + 1e-9),
+		moment: NewPoint(0, 0, 0),
+	}
+}
+
+func (n *octree) insert(b Body, depth int) {
+	mass := b.GetMass()
+	n.mass += mass
+	n.charge += b.GetCharge()
+	n.moment = n.moment.Add(b.GetPosition().Mul(mass))
+
+	if !n.split {
+		if len(n.bodies) == 0 || depth >= maxTreeDepth {
+			n.bodies = append(n.bodies, b)
+			return
+		}
+
+		old := n.bodies
+		n.bodies = nil
+		n.split = true
+		for _, o := range old {
+			n.child(o.GetPosition()).insert(o, depth+1)
+		}
+	}
+
+	n.child(b.GetPosition()).insert(b, depth+1)
+}
+
+func (n *octree) child(pos Point) *octree {
+	index := 0
+	offset := NewPoint(-n.size/2, -n.size/2, -n.size/2)
+	if pos.GetX() >= n.center.GetX() {
+		index |= 1
+		offset = offset.Add3(n.size, 0, 0)
+	}
+	if pos.GetY() >= n.center.GetY() {
+		index |= 2
+		offset = offset.Add3(0, n.size, 0)
+	}
+	if pos.GetZ() >= n.center.GetZ() {
+		index |= 4
+		offset = offset.Add3(0, 0, n.size)
+	}
+
+	if n.children[index] == nil {
+		n.children[index] = &octree{
+			center: n.center.Add(offset),
+			size:   n.size / 2,
+			moment: NewPoint(0, 0, 0),
+		}
+	}
+	return n.children[index]
+}
+
+func (n octree) contains(pos Point) bool {
+	diff := pos.Diff(n.center)
+	return math.Abs(diff.GetX()) <= n.size &&
+		math.Abs(diff.GetY()) <= n.size &&
+		math.Abs(diff.GetZ()) <= n.size
+}
+
+func (n octree) force(b Body, theta float64, grav GravFunc) Point {
+	res := NewPoint(0, 0, 0)
+
+	if !n.split {
+		for _, other := range n.bodies {
+			if other != b {
+				res = res.Add(grav(b, other))
+			}
+		}
+		return res
+	}
+
+	pos := b.GetPosition()
+	com := n.moment.Mul(1 / n.mass)
+	if d := com.Diff(pos).Magnitude(); 2*n.size < theta*d && !n.contains(pos) {
+		cell := body{
+			mass:     n.mass,
+			position: ToVector(com),
+			charge:   n.charge,
+		}
+		return grav(b, &cell)
+	}
+
+	for _, child := range n.children {
+		if child != nil {
+			res = res.Add(child.force(b, theta, grav))
+		}
+	}
+	return res
+}
@@ -11,18 +11,38 @@ type Body interface {
 	GetMass() float64
 	GetPosition() Point
 	SetPosition(Point)
+	GetPositionVector() Vector
+	SetPositionVector(Vector)
 	GetInertia() Point
 	SetInertia(v Point)
+	GetVelocity() Point
+	SetVelocity(Point)
+	GetVelocityVector() Vector
+	SetVelocityVector(Vector)
+	GetMomentum() Point
+	SetMomentum(Point)
+	GetRadius() float64
+	SetRadius(float64) error
+	GetDensity() float64
+	SetDensity(float64) error
+	IsPinned() bool
+	SetPinned(bool)
+	GetCharge() float64
+	SetCharge(float64)
 	Move(float64) error
 	Grav(Body) Point
+	SoftGrav(Body, float64) Point
 	String() string
 }
 
 type body struct {
 	name     string
 	mass     float64
-	position Point
-	inertia  Point
+	position Vector
+	velocity Vector
+	radius   float64
+	pinned   bool
+	charge   float64
 }
 
 // NewBody create a new Body
@@ -34,12 +54,32 @@ func NewBody(name string, mass, x, y, z float64) (Body, error) {
 	obj := body{
 		name:     name,
 		mass:     mass,
-		position: NewPoint(x, y, z),
-		inertia:  NewPoint(0, 0, 0),
+		position: Vector{x, y, z},
 	}
 	return &obj, nil
 }
 
+// NewTestParticle create a massless Body, which feels gravity but does not
+// exert it
+func NewTestParticle(name string, x, y, z float64) Body {
+	obj := body{
+		name:     name,
+		position: Vector{x, y, z},
+	}
+	return &obj
+}
+
+// NewBodyWithVelocity create a new Body moving with the given velocity
+func NewBodyWithVelocity(name string, mass, x, y, z, vx, vy, vz float64) (Body, error) {
+	b, err := NewBody(name, mass, x, y, z)
+	if err != nil {
+		return nil, err
+	}
+
+	b.SetVelocity(NewPoint(vx, vy, vz))
+	return b, nil
+}
+
 func (b body) GetName() string {
 	return b.name
 }
@@ -49,19 +89,123 @@ func (b body) GetMass() float64 {
 }
 
 func (b body) GetPosition() Point {
-	return b.position
+	return b.position.Point()
 }
 
 func (b *body) SetPosition(pos Point) {
+	b.position = ToVector(pos)
+}
+
+func (b body) GetPositionVector() Vector {
+	return b.position
+}
+
+func (b *body) SetPositionVector(pos Vector) {
 	b.position = pos
 }
 
+// GetInertia is the same as GetMomentum
 func (b body) GetInertia() Point {
-	return b.inertia
+	return b.GetMomentum()
 }
 
+// SetInertia is the same as SetMomentum
 func (b *body) SetInertia(v Point) {
-	b.inertia = v
+	b.SetMomentum(v)
+}
+
+func (b body) GetVelocity() Point {
+	return b.velocity.Point()
+}
+
+// SetVelocity sets the velocity; pinned bodies stay at rest, so it does
+// nothing on them
+func (b *body) SetVelocity(v Point) {
+	b.SetVelocityVector(ToVector(v))
+}
+
+func (b body) GetVelocityVector() Vector {
+	return b.velocity
+}
+
+func (b *body) SetVelocityVector(v Vector) {
+	if b.pinned {
+		return
+	}
+	b.velocity = v
+}
+
+func (b body) GetMomentum() Point {
+	return b.velocity.Mul(b.mass).Point()
+}
+
+// SetMomentum sets the velocity for the given momentum; test particles have
+// no momentum and pinned bodies stay at rest, so it does nothing on them
+func (b *body) SetMomentum(p Point) {
+	if b.mass == 0 {
+		return
+	}
+	b.SetVelocityVector(ToVector(p).Mul(1 / b.mass))
+}
+
+func (b body) GetRadius() float64 {
+	return b.radius
+}
+
+// SetRadius sets the physical radius; zero makes b a point mass
+func (b *body) SetRadius(radius float64) error {
+	if radius < 0 || math.IsNaN(radius) || math.IsInf(radius, 0) {
+		return fmt.Errorf("invalid radius: %v", radius)
+	}
+
+	b.radius = radius
+	return nil
+}
+
+// GetDensity returns the mean density, zero for point masses
+func (b body) GetDensity() float64 {
+	if b.radius == 0 {
+		return 0
+	}
+	return b.mass / (4 * math.Pi * b.radius * b.radius * b.radius / 3)
+}
+
+// SetDensity sets the radius of a homogeneous sphere with the given density
+func (b *body) SetDensity(density float64) error {
+	if density <= 0 || math.IsNaN(density) || math.IsInf(density, 0) {
+		return fmt.Errorf("invalid density: %v", density)
+	}
+
+	b.radius = math.Cbrt(3 * b.mass / (4 * math.Pi * density))
+	return nil
+}
+
+func (b body) IsPinned() bool {
+	return b.pinned
+}
+
+// SetPinned fixes b in place: it still exerts gravity, but never moves. A
+// pinned body is stopped, and neither integrators nor velocity or momentum
+// setters move it until unpinned.
+func (b *body) SetPinned(pinned bool) {
+	b.pinned = pinned
+	if pinned {
+		b.velocity = Vector{}
+	}
+}
+
+func (b body) GetCharge() float64 {
+	return b.charge
+}
+
+// SetCharge sets the electric charge, used by Coulomb pair forces; test
+// particles only feel forces proportional to their mass, so they carry no
+// charge and it does nothing on them
+func (b *body) SetCharge(charge float64) {
+	if b.mass == 0 {
+		return
+	}
+	b.charge = charge
 }
 
 func (b *body) Move(dt float64) error {
@@ -71,24 +215,57 @@ func (b *body) Move(dt float64) error {
 
 	mass := b.GetMass()
 
-	if mass <= 0 {
+	if mass < 0 {
 		return fmt.Errorf("invalid mass %v", mass)
 	}
 
-	inertia := b.GetInertia()
-	movement := NewPoint(
-		inertia.GetX()*dt/mass,
-		inertia.GetY()*dt/mass,
-		inertia.GetZ()*dt/mass,
-	)
-	b.position = b.position.Add(movement)
+	if b.pinned {
+		return nil
+	}
+
+	b.position = b.position.Add(b.velocity.Mul(dt))
 	return nil
 }
 
+// Grav returns the force b suffers from other, -G·m1·m2·r̂/d²
 func (b body) Grav(other Body) Point {
-	diff := b.position.Diff(other.GetPosition())
+	return b.SoftGrav(other, 0)
+}
+
+// SoftGrav returns the Plummer-softened force b suffers from other,
+// -G·m1·m2·r/(d²+ε²)^(3/2)
+func (b body) SoftGrav(other Body, softening float64) Point {
+	return newtonian(&b, other, G, softening)
+}
+
+// newtonian returns the softened force b1 suffers from b2 for the
+// gravitational constant g
+func newtonian(b1, b2 Body, g, softening float64) Point {
+	diff := b1.GetPositionVector().Sub(b2.GetPositionVector())
+	d2 := diff.Dot(diff) + softening*softening
+	if d2 == 0 {
+		return NewPoint(0, 0, 0)
+	}
+	return diff.Mul(-g * b1.GetMass() * b2.GetMass() / (d2 * math.Sqrt(d2))).Point()
+}
+
+// LegacyGrav returns the force b1 suffers from b2 as computed by Grav before
+// it became a true inverse-square vector: the full magnitude is applied to
+// every axis, keeping only the sign of the separation. It is kept for
+// scenarios tuned against that behaviour; see System.SetLegacyGravity.
+// Migrating such a scenario means retuning its initial inertias, since
+// diagonal separations now get up to √3 times less force, and, unless it
+// stepped with dt = 1, its timedeltas: Step used to add the raw force to
+// the momentum whatever dt, and now kicks by a·dt. The distance is
+// softened by the Plummer length ε as d²+ε².
+func LegacyGrav(b1, b2 Body, softening float64) Point {
+	return legacyGrav(b1, b2, G, softening)
+}
+
+func legacyGrav(b1, b2 Body, g, softening float64) Point {
+	diff := b1.GetPosition().Diff(b2.GetPosition())
 	d := diff.Magnitude()
-	f := G * b.mass * other.GetMass() / (d * d)
+	f := g * b1.GetMass() * b2.GetMass() / (d*d + softening*softening)
 
 	dx := diff.GetX()
 	if dx != 0 {
@@ -113,6 +290,6 @@ func (b body) Grav(other Body) Point {
 
 func (b body) String() string {
 	return fmt.Sprintf(
-		"{%v: %vKg @%v F%v}", b.name, b.mass, b.position, b.inertia,
+		"{%v: %vKg @%v F%v}", b.name, b.mass, b.position, b.GetMomentum(),
 	)
 }
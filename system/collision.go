@@ -0,0 +1,158 @@
+package gravity
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Merge records two overlapping bodies replaced by a single one
+type Merge struct {
+	Time   float64
+	Bodies [2]Body
+	Result Body
+}
+
+// Overlap tells whether two bodies touch; point masses only overlap when
+// they share the same position. Test particles do not interact, so they
+// never overlap each other.
+func Overlap(b1, b2 Body) bool {
+	if b1.GetMass() == 0 && b2.GetMass() == 0 {
+		return false
+	}
+
+	d := b1.GetPositionVector().Sub(b2.GetPositionVector()).Magnitude()
+	return d <= b1.GetRadius()+b2.GetRadius()
+}
+
+// MergeBodies creates the body resulting from an inelastic collision,
+// conserving mass, momentum, charge and volume. It is named after both bodies, the
+// heavier first, and placed at their centre of mass. When one of them is
+// pinned, the result is pinned in its place instead.
+func MergeBodies(b1, b2 Body) (Body, error) {
+	return mergeBodies(b1, b2, func(string) bool { return false })
+}
+
+// mergeBodies is MergeBodies suffixing the name with #2, #3… while taken
+// tells it is in use
+func mergeBodies(b1, b2 Body, taken func(string) bool) (Body, error) {
+	if b2.GetMass() > b1.GetMass() {
+		b1, b2 = b2, b1
+	}
+
+	m1 := b1.GetMass()
+	m2 := b2.GetMass()
+	mass := m1 + m2
+	pos := b1.GetPosition().Mul(m1).Add(b2.GetPosition().Mul(m2)).Mul(1 / mass)
+	switch {
+	case b1.IsPinned():
+		pos = b1.GetPosition()
+	case b2.IsPinned():
+		pos = b2.GetPosition()
+	}
+
+	name := fmt.Sprintf("%v+%v", b1.GetName(), b2.GetName())
+	for k := 2; taken(name); k++ {
+		name = fmt.Sprintf("%v+%v#%v", b1.GetName(), b2.GetName(), k)
+	}
+
+	res, err := NewBody(
+		name,
+		mass,
+		pos.GetX(), pos.GetY(), pos.GetZ(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	res.SetInertia(b1.GetInertia().Add(b2.GetInertia()))
+	res.SetPinned(b1.IsPinned() || b2.IsPinned())
+	res.SetCharge(b1.GetCharge() + b2.GetCharge())
+	r1 := b1.GetRadius()
+	r2 := b2.GetRadius()
+	if err := res.SetRadius(math.Cbrt(r1*r1*r1 + r2*r2*r2)); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s system) GetMerges() []Merge {
+	return s.merges
+}
+
+// resolveCollisions merges overlapping bodies until none is left, scanning
+// them by name so the outcome does not depend on map ordering; a merged
+// name already in use gets a #2, #3… suffix
+func (s *system) resolveCollisions() error {
+	for {
+		b1, b2 := s.findOverlap()
+		if b1 == nil {
+			return nil
+		}
+
+		res, err := mergeBodies(b1, b2, func(name string) bool {
+			other := s.bodies[name]
+			return other != nil && other != b1 && other != b2
+		})
+		if err != nil {
+			return err
+		}
+
+		s.RemoveBody(b1)
+		s.RemoveBody(b2)
+		if err := s.AddBody(res); err != nil {
+			s.bodies[b1.GetName()] = b1
+			s.bodies[b2.GetName()] = b2
+			return err
+		}
+		s.merges = append(s.merges, Merge{
+			Time:   s.time,
+			Bodies: [2]Body{b1, b2},
+			Result: res,
+		})
+	}
+}
+
+func (s system) findOverlap() (Body, Body) {
+	s.state.sync(s.bodies)
+	var res [2]Body
+	s.state.forEachPair(func(b1, b2 Body) bool {
+		if Overlap(b1, b2) {
+			res = [2]Body{b1, b2}
+			return false
+		}
+		return true
+	})
+	return res[0], res[1]
+}
+
+// forEachPair calls fn for the pairs of bodies, by name, until it returns
+// false. Test particles do not interact, so pairs of them are skipped: for
+// M massive bodies out of N, it costs O(M·N).
+func (st *state) forEachPair(fn func(b1, b2 Body) bool) {
+	bodies := st.bodies
+	massive := st.sources[:0]
+	for i, b := range bodies {
+		if b.GetMass() != 0 {
+			massive = append(massive, i)
+		}
+	}
+	st.sources = massive
+
+	for i, b1 := range bodies {
+		if b1.GetMass() != 0 {
+			for _, b2 := range bodies[i+1:] {
+				if !fn(b1, b2) {
+					return
+				}
+			}
+			continue
+		}
+
+		for _, j := range massive[sort.SearchInts(massive, i+1):] {
+			if !fn(b1, bodies[j]) {
+				return
+			}
+		}
+	}
+}
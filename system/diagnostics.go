@@ -0,0 +1,197 @@
+package gravity
+
+import "math"
+
+// Diagnostics holds the conserved quantities of a system at a given time
+type Diagnostics struct {
+	Time            float64
+	Kinetic         float64
+	Potential       float64
+	Momentum        Point
+	AngularMomentum Point
+	CenterOfMass    Point
+	VirialRatio     float64
+
+	// MomentumConserved is false when pinned bodies, external forces, pair
+	// forces breaking Newton's third law or the post-Newtonian correction
+	// keep momenta from being conserved, so their drift means nothing
+	MomentumConserved bool
+
+	// scales for the relative drift of vector quantities, which may be null
+	momentumScale, angularScale float64
+}
+
+// Drift holds relative changes of conserved quantities since a baseline
+type Drift struct {
+	Energy          float64
+	Momentum        float64
+	AngularMomentum float64
+}
+
+// Energy returns the total mechanical energy
+func (d Diagnostics) Energy() float64 {
+	return d.Kinetic + d.Potential
+}
+
+// DriftFrom compares d against a baseline; momenta are compared against the
+// largest sum of the bodies' magnitudes, since the totals are often null
+func (d Diagnostics) DriftFrom(baseline Diagnostics) Drift {
+	var res Drift
+	if e0 := baseline.Energy(); e0 != 0 {
+		res.Energy = math.Abs((d.Energy() - e0) / e0)
+	}
+	if scale := math.Max(d.momentumScale, baseline.momentumScale); scale != 0 {
+		res.Momentum = d.Momentum.Diff(baseline.Momentum).Magnitude() / scale
+	}
+	if scale := math.Max(d.angularScale, baseline.angularScale); scale != 0 {
+		res.AngularMomentum = d.AngularMomentum.Diff(baseline.AngularMomentum).Magnitude() / scale
+	}
+	return res
+}
+
+func (s system) KineticEnergy() float64 {
+	var res float64
+	for _, b := range s.bodyList() {
+		v := b.GetVelocity()
+		res += b.GetMass() * v.Dot(v) / 2
+	}
+	return res
+}
+
+// PotentialEnergy returns the softened gravitational potential energy,
+// -Σ G·m1·m2/√(d²+ε²) over every pair, or the sum of the pair force
+// potential when it is not Newtonian; it is NaN for pair forces with no
+// potential
+func (s system) PotentialEnergy() float64 {
+	if _, ok := s.pairForce.(newtonianForce); !ok {
+		return s.pairPotential()
+	}
+
+	length := len(s.bodies)
+	xs := make([]float64, length)
+	ys := make([]float64, length)
+	zs := make([]float64, length)
+	ms := make([]float64, length)
+	i := 0
+	for _, b := range s.bodyList() {
+		pos := b.GetPosition()
+		xs[i], ys[i], zs[i] = pos.GetX(), pos.GetY(), pos.GetZ()
+		ms[i] = b.GetMass()
+		i++
+	}
+
+	eps2 := s.softening * s.softening
+	var res float64
+	for i := 0; i < length-1; i++ {
+		var sum float64
+		for j := i + 1; j < length; j++ {
+			dx := xs[i] - xs[j]
+			dy := ys[i] - ys[j]
+			dz := zs[i] - zs[j]
+			sum += ms[j] / math.Sqrt(dx*dx+dy*dy+dz*dz+eps2)
+		}
+		res -= s.units.G() * ms[i] * sum
+	}
+	return res
+}
+
+func (s system) pairPotential() float64 {
+	p, ok := s.pairForce.(Potential)
+	if !ok {
+		return math.NaN()
+	}
+
+	bodies := s.bodyList()
+	var res float64
+	for i, b1 := range bodies {
+		for _, b2 := range bodies[i+1:] {
+			res += p.Potential(b1, b2, s.units.G(), s.softening)
+		}
+	}
+	return res
+}
+
+func (s system) Momentum() Point {
+	res := NewPoint(0, 0, 0)
+	for _, b := range s.bodyList() {
+		res = res.Add(b.GetInertia())
+	}
+	return res
+}
+
+// AngularMomentum returns Σ r×p about the origin
+func (s system) AngularMomentum() Point {
+	res := NewPoint(0, 0, 0)
+	for _, b := range s.bodyList() {
+		res = res.Add(b.GetPosition().Cross(b.GetInertia()))
+	}
+	return res
+}
+
+func (s system) CenterOfMass() Point {
+	res := NewPoint(0, 0, 0)
+	mass := s.TotalMass()
+	if mass == 0 {
+		return res
+	}
+	for _, b := range s.bodyList() {
+		res = res.Add(b.GetPosition().Mul(b.GetMass()))
+	}
+	return res.Mul(1 / mass)
+}
+
+// VirialRatio returns 2K/|U|, which is 1 for a virialised system
+func (s system) VirialRatio() float64 {
+	potential := s.PotentialEnergy()
+	if potential == 0 {
+		return 0
+	}
+	return 2 * s.KineticEnergy() / math.Abs(potential)
+}
+
+func (s system) Diagnose() Diagnostics {
+	kinetic := s.KineticEnergy()
+	potential := s.PotentialEnergy()
+	res := Diagnostics{
+		Time:            s.time,
+		Kinetic:         kinetic,
+		Potential:       potential,
+		Momentum:        s.Momentum(),
+		AngularMomentum: s.AngularMomentum(),
+		CenterOfMass:    s.CenterOfMass(),
+
+		MomentumConserved: len(s.forces) == 0 && s.central == nil && isAntisymmetric(s.pairForce),
+	}
+	if potential != 0 {
+		res.VirialRatio = 2 * kinetic / math.Abs(potential)
+	}
+	for _, b := range s.bodyList() {
+		if b.IsPinned() {
+			res.MomentumConserved = false
+		}
+		res.momentumScale += b.GetInertia().Magnitude()
+		res.angularScale += b.GetPosition().Cross(b.GetInertia()).Magnitude()
+	}
+	return res
+}
+
+// GetBaseline returns the diagnostics drift is measured against, taken
+// before the first step or by ResetBaseline
+func (s *system) GetBaseline() Diagnostics {
+	if s.baseline == nil {
+		s.ResetBaseline()
+	}
+	return *s.baseline
+}
+
+// ResetBaseline takes the current state as reference for GetDrift
+func (s *system) ResetBaseline() {
+	baseline := s.Diagnose()
+	s.baseline = &baseline
+}
+
+// GetDrift returns the relative drift of conserved quantities since the
+// baseline; momenta drifts only matter when Diagnostics.MomentumConserved
+func (s *system) GetDrift() Drift {
+	return s.Diagnose().DriftFrom(s.GetBaseline())
+}
@@ -0,0 +1,102 @@
+package gravity
+
+import (
+	"fmt"
+	"math"
+)
+
+// EscapePolicy tells when a body counts as escaped: energetically unbound
+// from the rest of the system and farther than Radius from its barycentre.
+// A null Radius turns detection off; Remove drops escaped bodies from the
+// system.
+type EscapePolicy struct {
+	Radius float64
+	Remove bool
+}
+
+// Escape records a body leaving the system
+type Escape struct {
+	Time           float64
+	Body           Body
+	Distance       float64
+	Speed          float64
+	EscapeVelocity float64
+	Removed        bool
+}
+
+func (s system) GetEscapePolicy() EscapePolicy {
+	return s.escapePolicy
+}
+
+func (s *system) SetEscapePolicy(policy EscapePolicy) error {
+	if policy.Radius < 0 || math.IsNaN(policy.Radius) {
+		return fmt.Errorf("invalid escape radius: %v", policy.Radius)
+	}
+
+	s.escapePolicy = policy
+	return nil
+}
+
+func (s system) GetEscapes() []Escape {
+	return s.escapes
+}
+
+// detectEscapes records bodies that became unbound beyond the policy
+// radius, scanning them by name; a body kept in the system is reported
+// again only after coming back
+func (s *system) detectEscapes() {
+	if s.escapePolicy.Radius == 0 {
+		return
+	}
+
+	var mass float64
+	moment := NewPoint(0, 0, 0)
+	momentum := NewPoint(0, 0, 0)
+	bodies := s.bodyList()
+	for _, b := range bodies {
+		mass += b.GetMass()
+		moment = moment.Add(b.GetPosition().Mul(b.GetMass()))
+		momentum = momentum.Add(b.GetMomentum())
+	}
+
+	g := s.units.G()
+	escaped := []Escape{}
+	for _, b := range bodies {
+		m := b.GetMass()
+		rest := mass - m
+		if b.IsPinned() || rest <= 0 {
+			continue
+		}
+
+		com := moment.Diff(b.GetPosition().Mul(m)).Mul(1 / rest)
+		velocity := momentum.Diff(b.GetMomentum()).Mul(1 / rest)
+		d := b.GetPosition().Diff(com).Magnitude()
+		speed := b.GetVelocity().Diff(velocity).Magnitude()
+		escape := math.Sqrt(2 * g * mass / d)
+
+		if d <= s.escapePolicy.Radius || speed < escape {
+			delete(s.escaped, b)
+			continue
+		}
+
+		if !s.escaped[b] {
+			escaped = append(escaped, Escape{
+				Time:           s.time,
+				Body:           b,
+				Distance:       d,
+				Speed:          speed,
+				EscapeVelocity: escape,
+				Removed:        s.escapePolicy.Remove,
+			})
+		}
+	}
+
+	for _, e := range escaped {
+		if e.Removed {
+			s.RemoveBody(e.Body)
+		} else {
+			s.escaped[e.Body] = true
+		}
+	}
+	s.escapes = append(s.escapes, escaped...)
+}
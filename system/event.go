@@ -0,0 +1,297 @@
+package gravity
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// EventKind tells what happened to the bodies of an Event
+type EventKind int
+
+const (
+	// Collision two bodies merged
+	Collision EventKind = iota
+	// EscapeEvent a body left the system, according to the escape policy
+	EscapeEvent
+	// CloseEncounter two bodies came closer than the encounter distance;
+	// test particles do not meet each other
+	CloseEncounter
+	// Periapsis a body passed its periapsis around the heavier body pulling
+	// it the hardest
+	Periapsis
+)
+
+// Event represents something that happened during a step. Bodies holds the
+// body concerned and, but for escapes, the other one involved; Result is
+// the merged body of a collision.
+type Event struct {
+	Kind     EventKind
+	Time     float64
+	Bodies   [2]Body
+	Result   Body
+	Distance float64
+	Speed    float64
+}
+
+// EventHandler reacts to an event between steps, so it may change, add or
+// remove bodies; returning an error stops the integration, and Step or
+// Advance return it, dropping the events left undelivered
+type EventHandler func(System, Event) error
+
+type subscription struct {
+	id      int
+	kind    EventKind
+	handler EventHandler
+}
+
+type approach struct {
+	primary Body
+	radial  float64
+}
+
+func (k EventKind) String() string {
+	switch k {
+	case Collision:
+		return "collision"
+	case EscapeEvent:
+		return "escape"
+	case CloseEncounter:
+		return "close encounter"
+	case Periapsis:
+		return "periapsis"
+	}
+	return fmt.Sprintf("EventKind(%d)", int(k))
+}
+
+// Subscribe registers handler for events of the given kind and returns the
+// subscription id; handlers are called in subscription order
+func (s *system) Subscribe(kind EventKind, handler EventHandler) (int, error) {
+	if handler == nil || kind < Collision || kind > Periapsis {
+		return 0, fmt.Errorf("invalid subscription to %v: %v", kind, handler)
+	}
+
+	s.lastSubscription++
+	s.subscriptions = append(s.subscriptions, subscription{s.lastSubscription, kind, handler})
+	return s.lastSubscription, nil
+}
+
+func (s *system) Unsubscribe(id int) bool {
+	for i, sub := range s.subscriptions {
+		if sub.id == id {
+			s.subscriptions = append(s.subscriptions[:i], s.subscriptions[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (s system) GetEncounterDistance() float64 {
+	return s.encounterDistance
+}
+
+// SetEncounterDistance sets how close two bodies must come for a close
+// encounter; zero turns it off
+func (s *system) SetEncounterDistance(distance float64) error {
+	if distance < 0 || math.IsNaN(distance) || math.IsInf(distance, 0) {
+		return fmt.Errorf("invalid encounter distance: %v", distance)
+	}
+
+	s.encounterDistance = distance
+	return nil
+}
+
+func (s system) subscribed(kind EventKind) bool {
+	for _, sub := range s.subscriptions {
+		if sub.kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// dispatchEvents gathers what happened since the last call and hands it to
+// the subscribers, ordered by time, kind and body names
+func (s *system) dispatchEvents() error {
+	if len(s.subscriptions) == 0 {
+		s.mergeMark = len(s.merges)
+		s.escapeMark = len(s.escapes)
+		s.encounters = nil
+		s.approaches = nil
+		return nil
+	}
+
+	events := []Event{}
+
+	for _, m := range s.merges[s.mergeMark:] {
+		r := m.Bodies[1].GetPosition().Diff(m.Bodies[0].GetPosition())
+		v := m.Bodies[1].GetVelocity().Diff(m.Bodies[0].GetVelocity())
+		events = append(events, Event{
+			Kind:     Collision,
+			Time:     m.Time,
+			Bodies:   m.Bodies,
+			Result:   m.Result,
+			Distance: r.Magnitude(),
+			Speed:    v.Magnitude(),
+		})
+	}
+	s.mergeMark = len(s.merges)
+
+	for _, e := range s.escapes[s.escapeMark:] {
+		events = append(events, Event{
+			Kind:     EscapeEvent,
+			Time:     e.Time,
+			Bodies:   [2]Body{e.Body, nil},
+			Distance: e.Distance,
+			Speed:    e.Speed,
+		})
+	}
+	s.escapeMark = len(s.escapes)
+
+	names := make([]string, 0, len(s.bodies))
+	for name := range s.bodies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if s.encounterDistance > 0 && s.subscribed(CloseEncounter) {
+		events = append(events, s.detectEncounters()...)
+	} else {
+		s.encounters = nil
+	}
+
+	if s.subscribed(Periapsis) {
+		events = append(events, s.detectPeriapses(names)...)
+	} else {
+		s.approaches = nil
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		e1, e2 := events[i], events[j]
+		if e1.Time != e2.Time {
+			return e1.Time < e2.Time
+		}
+		if e1.Kind != e2.Kind {
+			return e1.Kind < e2.Kind
+		}
+		for k := range e1.Bodies {
+			n1, n2 := eventName(e1.Bodies[k]), eventName(e2.Bodies[k])
+			if n1 != n2 {
+				return n1 < n2
+			}
+		}
+		return false
+	})
+
+	subscriptions := append([]subscription{}, s.subscriptions...)
+	for _, e := range events {
+		for _, sub := range subscriptions {
+			if sub.kind == e.Kind {
+				if err := sub.handler(s, e); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// detectEncounters reports pairs entering the encounter distance, but for
+// pairs of test particles
+func (s *system) detectEncounters() []Event {
+	events := []Event{}
+	current := make(map[[2]Body]bool)
+	s.state.sync(s.bodies)
+	s.state.forEachPair(func(b1, b2 Body) bool {
+		r := b2.GetPositionVector().Sub(b1.GetPositionVector())
+		d := r.Magnitude()
+		if d >= s.encounterDistance {
+			return true
+		}
+
+		pair := [2]Body{b1, b2}
+		current[pair] = true
+		if !s.encounters[pair] {
+			events = append(events, Event{
+				Kind:     CloseEncounter,
+				Time:     s.time,
+				Bodies:   pair,
+				Distance: d,
+				Speed:    b2.GetVelocityVector().Sub(b1.GetVelocityVector()).Magnitude(),
+			})
+		}
+		return true
+	})
+	s.encounters = current
+	return events
+}
+
+// detectPeriapses reports bodies whose radial velocity around their
+// dominant body turned from negative to positive since the last call
+func (s *system) detectPeriapses(names []string) []Event {
+	events := []Event{}
+	current := make(map[Body]approach)
+	massive := make([]string, 0, len(names))
+	for _, name := range names {
+		if s.bodies[name].GetMass() != 0 {
+			massive = append(massive, name)
+		}
+	}
+	for _, name := range names {
+		b := s.bodies[name]
+		if b.IsPinned() {
+			continue
+		}
+
+		primary := s.dominantBody(b, massive)
+		if primary == nil {
+			continue
+		}
+
+		r := b.GetPosition().Diff(primary.GetPosition())
+		v := b.GetVelocity().Diff(primary.GetVelocity())
+		radial := r.Dot(v)
+		if last, ok := s.approaches[b]; ok && last.primary == primary && last.radial < 0 && radial >= 0 {
+			events = append(events, Event{
+				Kind:     Periapsis,
+				Time:     s.time,
+				Bodies:   [2]Body{b, primary},
+				Distance: r.Magnitude(),
+				Speed:    v.Magnitude(),
+			})
+		}
+		current[b] = approach{primary, radial}
+	}
+	s.approaches = current
+	return events
+}
+
+// dominantBody returns the body pulling b the hardest among those heavier
+// than it; of two equal masses, the one named first is taken as heavier
+func (s system) dominantBody(b Body, names []string) Body {
+	var res Body
+	var strongest float64
+	mass := b.GetMass()
+	for _, name := range names {
+		other := s.bodies[name]
+		m := other.GetMass()
+		if other == b || m == 0 || m < mass || m == mass && name > b.GetName() {
+			continue
+		}
+
+		r := other.GetPosition().Diff(b.GetPosition())
+		pull := other.GetMass() / r.Dot(r)
+		if pull > strongest {
+			res = other
+			strongest = pull
+		}
+	}
+	return res
+}
+
+func eventName(b Body) string {
+	if b == nil {
+		return ""
+	}
+	return b.GetName()
+}
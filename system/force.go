@@ -0,0 +1,115 @@
+package gravity
+
+import (
+	"fmt"
+	"math"
+)
+
+// ExternalForce represents a force acting on bodies besides gravity; it may
+// depend on the simulation time and on the body state
+type ExternalForce interface {
+	Force(b Body, t float64) Point
+}
+
+type uniformField struct {
+	acceleration Point
+}
+
+type drag struct {
+	coefficient float64
+	quadratic   bool
+}
+
+type thrust struct {
+	name  string
+	force func(b Body, t float64) Point
+}
+
+// NewUniformField creates a field accelerating every body alike
+func NewUniformField(acceleration Point) ExternalForce {
+	return &uniformField{acceleration}
+}
+
+// NewLinearDrag creates a drag force -k·v
+func NewLinearDrag(k float64) (ExternalForce, error) {
+	if k < 0 || math.IsNaN(k) || math.IsInf(k, 0) {
+		return nil, fmt.Errorf("invalid drag coefficient: %v", k)
+	}
+
+	return &drag{coefficient: k}, nil
+}
+
+// NewQuadraticDrag creates a drag force -k·|v|·v
+func NewQuadraticDrag(k float64) (ExternalForce, error) {
+	if k < 0 || math.IsNaN(k) || math.IsInf(k, 0) {
+		return nil, fmt.Errorf("invalid drag coefficient: %v", k)
+	}
+
+	return &drag{coefficient: k, quadratic: true}, nil
+}
+
+// NewThrust creates a force acting only on the body with the given name,
+// as computed by the function
+func NewThrust(name string, force func(b Body, t float64) Point) (ExternalForce, error) {
+	if force == nil {
+		return nil, fmt.Errorf("invalid thrust for %v", name)
+	}
+
+	return &thrust{name, force}, nil
+}
+
+func (f uniformField) Force(b Body, t float64) Point {
+	return f.acceleration.Mul(b.GetMass())
+}
+
+func (f drag) Force(b Body, t float64) Point {
+	v := b.GetVelocity()
+	if f.quadratic {
+		return v.Mul(-f.coefficient * v.Magnitude())
+	}
+	return v.Mul(-f.coefficient)
+}
+
+func (f thrust) Force(b Body, t float64) Point {
+	if b.GetName() != f.name {
+		return NewPoint(0, 0, 0)
+	}
+	return f.force(b, t)
+}
+
+func (s system) GetForces() []ExternalForce {
+	return s.forces
+}
+
+func (s *system) AddForce(f ExternalForce) error {
+	if f == nil {
+		return fmt.Errorf("invalid force: %v", f)
+	}
+
+	s.forces = append(s.forces, f)
+	return nil
+}
+
+func (s *system) RemoveForce(f ExternalForce) bool {
+	for i, other := range s.forces {
+		if other == f {
+			s.forces = append(s.forces[:i:i], s.forces[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// externalForces adds the external accelerations; test particles get the
+// force a unit mass would feel
+func (s system) externalForces(bodies []Body, t float64, acc []Point) {
+	for _, f := range s.forces {
+		for i, b := range bodies {
+			if b.GetMass() == 0 {
+				acc[i] = acc[i].Add(f.Force(unitMass(b), t))
+			} else {
+				acc[i] = acc[i].Add(f.Force(b, t).Mul(1 / b.GetMass()))
+			}
+		}
+	}
+}
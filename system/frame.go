@@ -0,0 +1,37 @@
+package gravity
+
+// Recenter moves the system into its barycentric frame, subtracting the
+// centre of mass position and velocity from every body. Pinned bodies
+// already fix the frame and never move, so it does nothing while any body
+// is pinned.
+func (s *system) Recenter() {
+	mass := s.TotalMass()
+	if mass == 0 {
+		return
+	}
+	for _, b := range s.bodies {
+		if b.IsPinned() {
+			return
+		}
+	}
+
+	com := s.CenterOfMass()
+	velocity := s.Momentum().Mul(1 / mass)
+	for _, b := range s.bodies {
+		b.SetPosition(b.GetPosition().Diff(com))
+		b.SetVelocity(b.GetVelocity().Diff(velocity))
+	}
+}
+
+func (s system) IsAutoRecenter() bool {
+	return s.autoRecenter
+}
+
+// SetAutoRecenter makes the system recenter now and every time a body is
+// added, so long runs stay in the barycentric frame
+func (s *system) SetAutoRecenter(auto bool) {
+	s.autoRecenter = auto
+	if auto {
+		s.Recenter()
+	}
+}
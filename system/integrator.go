@@ -0,0 +1,152 @@
+package gravity
+
+// AccelFunc computes the acceleration of each body at the time t
+type AccelFunc func(bodies []Body, t float64) []Point
+
+// Integrator advances bodies through a timedelta
+type Integrator interface {
+	Integrate(bodies []Body, t, dt float64, accel AccelFunc) error
+}
+
+type euler struct{}
+
+type leapfrog struct{}
+
+type verlet struct{}
+
+type rk4 struct{}
+
+type legacyEuler struct{}
+
+// NewEuler creates the kick-then-drift Euler integrator, the System default
+func NewEuler() Integrator {
+	return euler{}
+}
+
+// NewLeapfrog creates a symplectic drift-kick-drift leapfrog integrator
+func NewLeapfrog() Integrator {
+	return leapfrog{}
+}
+
+// NewVelocityVerlet creates a symplectic kick-drift-kick Velocity Verlet
+// integrator
+func NewVelocityVerlet() Integrator {
+	return verlet{}
+}
+
+// NewRK4 creates a classic fourth order Runge-Kutta integrator
+func NewRK4() Integrator {
+	return rk4{}
+}
+
+// NewLegacyEuler creates the integrator Step used before integrators were
+// pluggable: each body's momentum grows by the raw net force, that is its
+// velocity by the acceleration over one second whatever dt, and it then
+// drifts for dt. Along with System.SetLegacyGravity it replays scenarios
+// tuned against that behaviour; it is only consistent for dt = 1.
+func NewLegacyEuler() Integrator {
+	return legacyEuler{}
+}
+
+func (euler) Integrate(bodies []Body, t, dt float64, accel AccelFunc) error {
+	kick(bodies, accel(bodies, t), dt)
+	return drift(bodies, dt)
+}
+
+func (legacyEuler) Integrate(bodies []Body, t, dt float64, accel AccelFunc) error {
+	kick(bodies, accel(bodies, t), 1)
+	return drift(bodies, dt)
+}
+
+func (leapfrog) Integrate(bodies []Body, t, dt float64, accel AccelFunc) error {
+	if err := drift(bodies, dt/2); err != nil {
+		return err
+	}
+	kick(bodies, accel(bodies, t+dt/2), dt)
+	return drift(bodies, dt/2)
+}
+
+func (verlet) Integrate(bodies []Body, t, dt float64, accel AccelFunc) error {
+	kick(bodies, accel(bodies, t), dt/2)
+	if err := drift(bodies, dt); err != nil {
+		return err
+	}
+	kick(bodies, accel(bodies, t+dt), dt/2)
+	return nil
+}
+
+func (rk4) Integrate(bodies []Body, t, dt float64, accel AccelFunc) error {
+	length := len(bodies)
+	x0 := make([]Point, length)
+	for i, b := range bodies {
+		x0[i] = b.GetPosition()
+	}
+	v0 := velocities(bodies)
+
+	kx1 := v0
+	kv1 := accel(bodies, t)
+	setState(bodies, x0, v0, kx1, kv1, dt/2)
+
+	kx2 := velocities(bodies)
+	kv2 := accel(bodies, t+dt/2)
+	setState(bodies, x0, v0, kx2, kv2, dt/2)
+
+	kx3 := velocities(bodies)
+	kv3 := accel(bodies, t+dt/2)
+	setState(bodies, x0, v0, kx3, kv3, dt)
+
+	kx4 := velocities(bodies)
+	kv4 := accel(bodies, t+dt)
+
+	for i, b := range bodies {
+		if b.IsPinned() {
+			continue
+		}
+		dx := kx1[i].Add(kx2[i].Mul(2)).Add(kx3[i].Mul(2)).Add(kx4[i])
+		dv := kv1[i].Add(kv2[i].Mul(2)).Add(kv3[i].Mul(2)).Add(kv4[i])
+		b.SetPosition(x0[i].Add(dx.Mul(dt / 6)))
+		b.SetVelocity(v0[i].Add(dv.Mul(dt / 6)))
+	}
+	return nil
+}
+
+func kick(bodies []Body, acc []Point, dt float64) {
+	for i, b := range bodies {
+		if !b.IsPinned() {
+			b.SetVelocity(b.GetVelocity().Add(acc[i].Mul(dt)))
+		}
+	}
+}
+
+func drift(bodies []Body, dt float64) error {
+	for _, b := range bodies {
+		if err := b.Move(dt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// velocities returns the velocity of each body, null for pinned ones
+func velocities(bodies []Body) []Point {
+	res := make([]Point, len(bodies))
+	for i, b := range bodies {
+		if b.IsPinned() {
+			res[i] = NewPoint(0, 0, 0)
+		} else {
+			res[i] = b.GetVelocity()
+		}
+	}
+	return res
+}
+
+// setState places bodies at x0 + dx·h with velocity v0 + dv·h, leaving
+// pinned ones alone
+func setState(bodies []Body, x0, v0, dx, dv []Point, h float64) {
+	for i, b := range bodies {
+		if !b.IsPinned() {
+			b.SetPosition(x0[i].Add(dx[i].Mul(h)))
+			b.SetVelocity(v0[i].Add(dv[i].Mul(h)))
+		}
+	}
+}
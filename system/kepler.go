@@ -0,0 +1,278 @@
+package gravity
+
+import (
+	"fmt"
+	"math"
+)
+
+// parabolicTolerance is how close to 1 an eccentricity must be for a state
+// to be taken as parabolic
+const parabolicTolerance = 1e-12
+
+// Elements represents a Keplerian orbit; angles are in radians.
+//
+// Hyperbolic orbits (e > 1) take a negative semi-major axis. Parabolic
+// orbits (e = 1) have no finite semi-major axis, so SemiMajorAxis holds the
+// periapsis distance instead, and MeanAnomaly is Barker's D + D³/3, where
+// D = tan(ν/2).
+type Elements struct {
+	SemiMajorAxis            float64
+	Eccentricity             float64
+	Inclination              float64
+	LongitudeOfAscendingNode float64
+	ArgumentOfPeriapsis      float64
+	MeanAnomaly              float64
+}
+
+// SetElements places b on the orbit described by el around primary
+func (s system) SetElements(b, primary Body, el Elements) error {
+	mu := s.units.G() * (primary.GetMass() + b.GetMass())
+	r, v, err := stateFromElements(mu, el)
+	if err != nil {
+		return err
+	}
+
+	b.SetPosition(primary.GetPosition().Add(r))
+	b.SetVelocity(primary.GetVelocity().Add(v))
+	return nil
+}
+
+// GetElements returns the orbit of b around primary
+func (s system) GetElements(b, primary Body) (Elements, error) {
+	mu := s.units.G() * (primary.GetMass() + b.GetMass())
+	r := b.GetPosition().Diff(primary.GetPosition())
+	v := b.GetVelocity().Diff(primary.GetVelocity())
+	return elementsFromState(mu, r, v)
+}
+
+func stateFromElements(mu float64, el Elements) (Point, Point, error) {
+	e := el.Eccentricity
+	a := el.SemiMajorAxis
+	var r, nu, p float64
+
+	switch {
+	case e < 0 || math.IsNaN(e):
+		return nil, nil, fmt.Errorf("invalid eccentricity: %v", e)
+
+	case e < 1:
+		if a <= 0 {
+			return nil, nil, fmt.Errorf("invalid semi-major axis for elliptic orbit: %v", a)
+		}
+		E := solveElliptic(el.MeanAnomaly, e)
+		nu = 2 * math.Atan2(math.Sqrt(1+e)*math.Sin(E/2), math.Sqrt(1-e)*math.Cos(E/2))
+		r = a * (1 - e*math.Cos(E))
+		p = a * (1 - e*e)
+
+	case e == 1:
+		if a <= 0 {
+			return nil, nil, fmt.Errorf("invalid periapsis distance for parabolic orbit: %v", a)
+		}
+		D := solveParabolic(el.MeanAnomaly)
+		nu = 2 * math.Atan(D)
+		r = a * (1 + D*D)
+		p = 2 * a
+
+	default:
+		if a >= 0 {
+			return nil, nil, fmt.Errorf("invalid semi-major axis for hyperbolic orbit: %v", a)
+		}
+		H := solveHyperbolic(el.MeanAnomaly, e)
+		nu = 2 * math.Atan(math.Sqrt((e+1)/(e-1))*math.Tanh(H/2))
+		r = a * (1 - e*math.Cosh(H))
+		p = a * (1 - e*e)
+	}
+
+	speed := math.Sqrt(mu / p)
+	pos := rotateOrbit(NewPoint(r*math.Cos(nu), r*math.Sin(nu), 0), el)
+	vel := rotateOrbit(NewPoint(-speed*math.Sin(nu), speed*(e+math.Cos(nu)), 0), el)
+	return pos, vel, nil
+}
+
+func elementsFromState(mu float64, r, v Point) (Elements, error) {
+	var el Elements
+	d := r.Magnitude()
+	h := r.Cross(v)
+	if d == 0 || h.Magnitude() == 0 {
+		return el, fmt.Errorf("degenerate orbit: r=%v v=%v", r, v)
+	}
+
+	hat := h.Mul(1 / h.Magnitude())
+	ecc := v.Cross(h).Mul(1 / mu).Diff(r.Mul(1 / d))
+	e := ecc.Magnitude()
+
+	node := NewPoint(-h.GetY(), h.GetX(), 0)
+	reference := NewPoint(1, 0, 0)
+	if n := node.Magnitude(); n > 1e-15*h.Magnitude() {
+		reference = node.Mul(1 / n)
+		el.LongitudeOfAscendingNode = normalizeAngle(math.Atan2(node.GetY(), node.GetX()))
+	}
+	el.Inclination = math.Acos(math.Max(-1, math.Min(1, hat.GetZ())))
+
+	var nu float64
+	if e > 1e-15 {
+		el.ArgumentOfPeriapsis = normalizeAngle(math.Atan2(hat.Dot(reference.Cross(ecc)), reference.Dot(ecc)))
+		nu = math.Atan2(hat.Dot(ecc.Cross(r)), ecc.Dot(r))
+	} else {
+		e = 0
+		nu = math.Atan2(hat.Dot(reference.Cross(r)), reference.Dot(r))
+	}
+	el.Eccentricity = e
+
+	p := h.Dot(h) / mu
+	switch {
+	case math.Abs(e-1) < parabolicTolerance:
+		el.Eccentricity = 1
+		el.SemiMajorAxis = p / 2
+		D := math.Tan(nu / 2)
+		el.MeanAnomaly = D + D*D*D/3
+
+	case e < 1:
+		el.SemiMajorAxis = p / (1 - e*e)
+		E := 2 * math.Atan2(math.Sqrt(1-e)*math.Sin(nu/2), math.Sqrt(1+e)*math.Cos(nu/2))
+		el.MeanAnomaly = normalizeAngle(E - e*math.Sin(E))
+
+	default:
+		el.SemiMajorAxis = p / (1 - e*e)
+		H := 2 * math.Atanh(math.Sqrt((e-1)/(e+1))*math.Tan(nu/2))
+		el.MeanAnomaly = e*math.Sinh(H) - H
+	}
+
+	return el, nil
+}
+
+// rotateOrbit takes a perifocal vector to the reference frame
+func rotateOrbit(p Point, el Elements) Point {
+	cosO, sinO := math.Cos(el.LongitudeOfAscendingNode), math.Sin(el.LongitudeOfAscendingNode)
+	cosW, sinW := math.Cos(el.ArgumentOfPeriapsis), math.Sin(el.ArgumentOfPeriapsis)
+	cosI, sinI := math.Cos(el.Inclination), math.Sin(el.Inclination)
+	x, y := p.GetX(), p.GetY()
+
+	return NewPoint(
+		(cosO*cosW-sinO*sinW*cosI)*x+(-cosO*sinW-sinO*cosW*cosI)*y,
+		(sinO*cosW+cosO*sinW*cosI)*x+(-sinO*sinW+cosO*cosW*cosI)*y,
+		sinW*sinI*x+cosW*sinI*y,
+	)
+}
+
+// solveElliptic solves Kepler's equation E - e·sin E = M
+func solveElliptic(M, e float64) float64 {
+	M = math.Remainder(M, 2*math.Pi)
+	E := M
+	if e > 0.8 {
+		E = math.Copysign(math.Pi, M)
+	}
+	for i := 0; i < 50; i++ {
+		dE := (E - e*math.Sin(E) - M) / (1 - e*math.Cos(E))
+		E -= dE
+		if math.Abs(dE) < 1e-15 {
+			break
+		}
+	}
+	return E
+}
+
+// solveHyperbolic solves e·sinh H - H = M
+func solveHyperbolic(M, e float64) float64 {
+	H := math.Asinh(M / e)
+	for i := 0; i < 100; i++ {
+		dH := (e*math.Sinh(H) - H - M) / (e*math.Cosh(H) - 1)
+		H -= dH
+		if math.Abs(dH) < 1e-15*math.Max(1, math.Abs(H)) {
+			break
+		}
+	}
+	return H
+}
+
+// solveParabolic solves Barker's equation D + D³/3 = M by Cardano's
+// formula, written as A - 1/A to avoid cancellation
+func solveParabolic(M float64) float64 {
+	A := math.Cbrt(3*math.Abs(M)/2 + math.Sqrt(9*M*M/4+1))
+	return math.Copysign(A-1/A, M)
+}
+
+func normalizeAngle(angle float64) float64 {
+	angle = math.Mod(angle, 2*math.Pi)
+	if angle < 0 {
+		angle += 2 * math.Pi
+	}
+	return angle
+}
+
+// keplerDrift moves the relative state (r, v) of a two-body orbit with the
+// gravitational parameter mu through dt, solving Kepler's equation in
+// universal variables by Laguerre–Conway iteration
+func keplerDrift(mu float64, r, v Vector, dt float64) (Vector, Vector, error) {
+	r0 := r.Magnitude()
+	if r0 == 0 || mu == 0 || dt == 0 {
+		return r.Add(v.Mul(dt)), v, nil
+	}
+
+	sqrtMu := math.Sqrt(mu)
+	sigma := r.Dot(v) / sqrtMu
+	alpha := 2/r0 - v.Dot(v)/mu
+	beta := 1 - alpha*r0
+	if alpha > 0 {
+		// whole periods change nothing
+		period := 2 * math.Pi / (sqrtMu * alpha * math.Sqrt(alpha))
+		dt = math.Remainder(dt, period)
+	}
+
+	chi := sqrtMu * dt / r0
+	if alpha > 0 {
+		chi = sqrtMu * alpha * dt
+	}
+	const n = 5
+	converged := false
+	for i := 0; i < 100 && !converged; i++ {
+		z := alpha * chi * chi
+		c, s := stumpff(z)
+		f := sigma*chi*chi*c + beta*chi*chi*chi*s + r0*chi - sqrtMu*dt
+		df := sigma*chi*(1-z*s) + beta*chi*chi*c + r0
+		ddf := sigma*(1-z*c) + beta*chi*(1-z*s)
+		root := math.Sqrt(math.Abs((n-1)*(n-1)*df*df - n*(n-1)*f*ddf))
+		delta := n * f / (df + math.Copysign(root, df))
+		chi -= delta
+		converged = math.Abs(delta) <= 1e-14*math.Abs(chi) || delta == 0
+	}
+	if !converged || math.IsNaN(chi) {
+		return r, v, fmt.Errorf("Kepler's equation did not converge: r=%v v=%v dt=%v", r, v, dt)
+	}
+
+	chi2 := chi * chi
+	c, s := stumpff(alpha * chi2)
+	f := 1 - chi2*c/r0
+	g := dt - chi2*chi*s/sqrtMu
+	pos := r.Mul(f).Add(v.Mul(g))
+	d := pos.Magnitude()
+	df := sqrtMu * chi * (alpha*chi2*s - 1) / (d * r0)
+	dg := 1 - chi2*c/d
+	return pos, r.Mul(df).Add(v.Mul(dg)), nil
+}
+
+// stumpff returns the Stumpff functions C(z) and S(z), by their series
+// near zero, where the closed forms cancel
+func stumpff(z float64) (float64, float64) {
+	switch {
+	case z > 4:
+		x := math.Sqrt(z)
+		sin := math.Sin(x / 2)
+		return 2 * sin * sin / z, (x - math.Sin(x)) / (z * x)
+
+	case z < -4:
+		x := math.Sqrt(-z)
+		sinh := math.Sinh(x / 2)
+		return 2 * sinh * sinh / -z, (math.Sinh(x) - x) / (-z * x)
+	}
+
+	// C = Σ (-z)ᵏ/(2k+2)!, S = Σ (-z)ᵏ/(2k+3)!
+	c, s := 0.0, 0.0
+	term := 0.5
+	for k := 0; k < 20; k++ {
+		c += term
+		term /= float64(2*k + 3)
+		s += term
+		term *= -z / float64(2*k+4)
+	}
+	return c, s
+}
@@ -0,0 +1,197 @@
+package gravity
+
+import (
+	"fmt"
+	"math"
+)
+
+// RotatingFrame represents the frame co-rotating with a pair of bodies on a
+// near-circular orbit: the origin is their barycentre, X points from the
+// primary to the secondary, Z along the orbital angular momentum and Y along
+// the secondary's motion
+type RotatingFrame struct {
+	Origin     Point
+	Velocity   Point
+	X, Y, Z    Point
+	Rate       float64 // angular velocity
+	Separation float64
+	MassRatio  float64 // m2 / (m1 + m2)
+}
+
+// GetRotatingFrame returns the frame co-rotating with primary and secondary
+// at their current state
+func (s system) GetRotatingFrame(primary, secondary Body) (RotatingFrame, error) {
+	var frame RotatingFrame
+	m1, m2 := primary.GetMass(), secondary.GetMass()
+	if m1+m2 <= 0 {
+		return frame, fmt.Errorf("%v and %v have no mass", primary.GetName(), secondary.GetName())
+	}
+
+	r := secondary.GetPosition().Diff(primary.GetPosition())
+	v := secondary.GetVelocity().Diff(primary.GetVelocity())
+	h := r.Cross(v)
+	d := r.Magnitude()
+	if d == 0 || h.Magnitude() == 0 {
+		return frame, fmt.Errorf("degenerate orbit: r=%v v=%v", r, v)
+	}
+
+	mass := m1 + m2
+	frame.Origin = primary.GetPosition().Mul(m1 / mass).Add(secondary.GetPosition().Mul(m2 / mass))
+	frame.Velocity = primary.GetVelocity().Mul(m1 / mass).Add(secondary.GetVelocity().Mul(m2 / mass))
+	frame.X = r.Mul(1 / d)
+	frame.Z = h.Mul(1 / h.Magnitude())
+	frame.Y = frame.Z.Cross(frame.X)
+	frame.Rate = h.Magnitude() / (d * d)
+	frame.Separation = d
+	frame.MassRatio = m2 / mass
+	return frame, nil
+}
+
+// ToFrame takes an inertial state into the rotating frame
+func (f RotatingFrame) ToFrame(pos, vel Point) (Point, Point) {
+	r := pos.Diff(f.Origin)
+	v := vel.Diff(f.Velocity).Diff(f.Z.Mul(f.Rate).Cross(r))
+	return NewPoint(r.Dot(f.X), r.Dot(f.Y), r.Dot(f.Z)),
+		NewPoint(v.Dot(f.X), v.Dot(f.Y), v.Dot(f.Z))
+}
+
+// FromFrame takes a state in the rotating frame back to the inertial one
+func (f RotatingFrame) FromFrame(pos, vel Point) (Point, Point) {
+	r := f.X.Mul(pos.GetX()).Add(f.Y.Mul(pos.GetY())).Add(f.Z.Mul(pos.GetZ()))
+	v := f.X.Mul(vel.GetX()).Add(f.Y.Mul(vel.GetY())).Add(f.Z.Mul(vel.GetZ()))
+	return f.Origin.Add(r), f.Velocity.Add(v).Add(f.Z.Mul(f.Rate).Cross(r))
+}
+
+// Force returns the fictitious forces of the frame on bodies given in its
+// coordinates, which rotate about Z: centrifugal m·Ω²·(x, y, 0) and
+// Coriolis -2m·Ω×v
+func (f RotatingFrame) Force() ExternalForce {
+	return &frameForce{f.Rate}
+}
+
+type frameForce struct {
+	rate float64
+}
+
+func (f frameForce) Force(b Body, t float64) Point {
+	pos, vel := b.GetPositionVector(), b.GetVelocityVector()
+	w := f.rate
+	return NewPoint(
+		w*w*pos.X+2*w*vel.Y,
+		w*w*pos.Y-2*w*vel.X,
+		0,
+	).Mul(b.GetMass())
+}
+
+// CoRotatingSystem returns a new system in the frame co-rotating with
+// primary and secondary, for the circular restricted three-body problem:
+// both are pinned in their frame places, the particles are added as test
+// particles at their frame state, and the frame forces act on them. It
+// keeps the integrator, units and softening of s; take states back with
+// FromFrame of GetRotatingFrame, called on s.
+func (s system) CoRotatingSystem(primary, secondary Body, particles ...Body) (System, error) {
+	frame, err := s.GetRotatingFrame(primary, secondary)
+	if err != nil {
+		return nil, err
+	}
+
+	res, _ := NewSystemWithIntegrator(s.integrator)
+	res.SetUnits(s.units)
+	res.SetSoftening(s.softening)
+	res.AddForce(frame.Force())
+	for _, b := range []Body{primary, secondary} {
+		pos, _ := frame.ToFrame(b.GetPosition(), b.GetVelocity())
+		pinned, err := NewBody(b.GetName(), b.GetMass(), pos.GetX(), pos.GetY(), pos.GetZ())
+		if err != nil {
+			return nil, err
+		}
+		pinned.SetRadius(b.GetRadius())
+		pinned.SetPinned(true)
+		res.AddBody(pinned)
+	}
+	for _, b := range particles {
+		pos, vel := frame.ToFrame(b.GetPosition(), b.GetVelocity())
+		particle := NewTestParticle(b.GetName(), pos.GetX(), pos.GetY(), pos.GetZ())
+		particle.SetVelocity(vel)
+		particle.SetRadius(b.GetRadius())
+		if err := res.AddBody(particle); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// LagrangePoints returns the positions of L1 to L5 of primary and
+// secondary; L1 lies between them, L2 beyond the secondary, L3 beyond the
+// primary, and L4 leads the secondary by 60°
+func (s system) LagrangePoints(primary, secondary Body) ([5]Point, error) {
+	var res [5]Point
+	frame, err := s.GetRotatingFrame(primary, secondary)
+	if err != nil {
+		return res, err
+	}
+
+	mu := frame.MassRatio
+	x1, x2 := -mu, 1-mu
+	rotating := [5]Point{
+		NewPoint(collinearPoint(mu, x1, x2), 0, 0),
+		NewPoint(collinearPoint(mu, x2, 2), 0, 0),
+		NewPoint(collinearPoint(mu, -2, x1), 0, 0),
+		NewPoint(0.5-mu, math.Sqrt(3)/2, 0),
+		NewPoint(0.5-mu, -math.Sqrt(3)/2, 0),
+	}
+
+	zero := NewPoint(0, 0, 0)
+	for i, p := range rotating {
+		res[i], _ = frame.FromFrame(p.Mul(frame.Separation), zero)
+	}
+	return res, nil
+}
+
+// JacobiConstant returns C = 2Φ - v² of b in the frame co-rotating with
+// primary and secondary, Φ being the gravitational plus centrifugal
+// potential; it is conserved for a test particle of the circular restricted
+// three-body problem
+func (s system) JacobiConstant(b, primary, secondary Body) (float64, error) {
+	frame, err := s.GetRotatingFrame(primary, secondary)
+	if err != nil {
+		return 0, err
+	}
+
+	pos := b.GetPosition()
+	r1 := pos.Diff(primary.GetPosition()).Magnitude()
+	r2 := pos.Diff(secondary.GetPosition()).Magnitude()
+	if r1 == 0 || r2 == 0 {
+		return 0, fmt.Errorf("%v sits on a primary", b.GetName())
+	}
+
+	r, v := frame.ToFrame(pos, b.GetVelocity())
+	g := s.units.G()
+	rho := frame.Rate * math.Hypot(r.GetX(), r.GetY())
+	phi := g*primary.GetMass()/r1 + g*secondary.GetMass()/r2 + rho*rho/2
+	return 2*phi - v.Dot(v), nil
+}
+
+// collinearPoint finds by bisection the equilibrium on the X axis between
+// low and high, in units of the separation; the effective force grows
+// monotonically between the primaries
+func collinearPoint(mu, low, high float64) float64 {
+	force := func(x float64) float64 {
+		d1 := x + mu
+		d2 := x - 1 + mu
+		return x - (1-mu)*d1/math.Abs(d1*d1*d1) - mu*d2/math.Abs(d2*d2*d2)
+	}
+
+	for i := 0; i < 200; i++ {
+		mid := (low + high) / 2
+		if mid == low || mid == high {
+			break
+		}
+		if force(mid) < 0 {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return (low + high) / 2
+}
@@ -0,0 +1,108 @@
+package gravity
+
+import (
+	"fmt"
+	"math"
+)
+
+// SetCircularOrbit sets the velocity of b for a circular orbit around
+// primary, in the plane perpendicular to normal, which must be orthogonal
+// to the separation. A nil primary stands for the barycentre of every other
+// body in the system.
+func (s system) SetCircularOrbit(b, primary Body, normal Point) error {
+	primary, r, err := s.separation(b, primary)
+	if err != nil {
+		return err
+	}
+
+	n := normal.Magnitude()
+	d := r.Magnitude()
+	if n == 0 || math.Abs(r.Dot(normal)) > 1e-9*n*d {
+		return fmt.Errorf("invalid orbit normal %v for separation %v", normal, r)
+	}
+
+	direction := normal.Cross(r).Mul(1 / (n * d))
+	speed := math.Sqrt(s.units.G() * (primary.GetMass() + b.GetMass()) / d)
+	b.SetVelocity(primary.GetVelocity().Add(direction.Mul(speed)))
+	return nil
+}
+
+// CircularVelocity returns the speed relative to primary for a circular
+// orbit at the current distance; a nil primary is the barycentre of the rest
+func (s system) CircularVelocity(b, primary Body) (float64, error) {
+	primary, r, err := s.separation(b, primary)
+	if err != nil {
+		return 0, err
+	}
+	return math.Sqrt(s.units.G() * (primary.GetMass() + b.GetMass()) / r.Magnitude()), nil
+}
+
+// EscapeVelocity returns the speed relative to primary needed to escape
+// from the current distance; a nil primary is the barycentre of the rest
+func (s system) EscapeVelocity(b, primary Body) (float64, error) {
+	primary, r, err := s.separation(b, primary)
+	if err != nil {
+		return 0, err
+	}
+	return math.Sqrt(2 * s.units.G() * (primary.GetMass() + b.GetMass()) / r.Magnitude()), nil
+}
+
+// OrbitalPeriod returns the period of the current orbit of b around
+// primary; a nil primary is the barycentre of the rest
+func (s system) OrbitalPeriod(b, primary Body) (float64, error) {
+	primary, r, err := s.separation(b, primary)
+	if err != nil {
+		return 0, err
+	}
+
+	mu := s.units.G() * (primary.GetMass() + b.GetMass())
+	v := b.GetVelocity().Diff(primary.GetVelocity()).Magnitude()
+	energy := v*v/2 - mu/r.Magnitude()
+	if energy >= 0 {
+		return 0, fmt.Errorf("%v is not bound", b.GetName())
+	}
+
+	a := -mu / (2 * energy)
+	return 2 * math.Pi * math.Sqrt(a*a*a/mu), nil
+}
+
+// separation resolves a nil primary into a body standing for the rest of
+// the system, and returns it with the position of b relative to it
+func (s system) separation(b, primary Body) (Body, Point, error) {
+	if primary == nil {
+		primary = s.barycentreWithout(b)
+		if primary == nil {
+			return nil, nil, fmt.Errorf("no body for %v to orbit", b.GetName())
+		}
+	}
+
+	r := b.GetPosition().Diff(primary.GetPosition())
+	if r.Magnitude() == 0 {
+		return nil, nil, fmt.Errorf("%v and %v share the same position", b.GetName(), primary.GetName())
+	}
+	return primary, r, nil
+}
+
+func (s system) barycentreWithout(b Body) Body {
+	var mass float64
+	moment := NewPoint(0, 0, 0)
+	momentum := NewPoint(0, 0, 0)
+	for _, other := range s.bodyList() {
+		if other != b {
+			mass += other.GetMass()
+			moment = moment.Add(other.GetPosition().Mul(other.GetMass()))
+			momentum = momentum.Add(other.GetMomentum())
+		}
+	}
+
+	if mass == 0 {
+		return nil
+	}
+
+	return &body{
+		name:     "barycentre",
+		mass:     mass,
+		position: ToVector(moment.Mul(1 / mass)),
+		velocity: ToVector(momentum.Mul(1 / mass)),
+	}
+}
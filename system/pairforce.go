@@ -0,0 +1,220 @@
+package gravity
+
+import (
+	"fmt"
+	"math"
+)
+
+// Physical constants in SI
+const (
+	CoulombConstant  = 8.9875517923e+9
+	MONDAcceleration = 1.2e-10
+)
+
+// PairForce represents the interaction law between two bodies: Force
+// returns the force b1 suffers from b2, given the gravitational constant of
+// the system units and its softening length
+type PairForce interface {
+	Force(b1, b2 Body, g, softening float64) Point
+}
+
+// Potential is implemented by pair forces deriving from a potential energy,
+// which conservation diagnostics need
+type Potential interface {
+	Potential(b1, b2 Body, g, softening float64) float64
+}
+
+// Antisymmetric is implemented by pair forces telling whether they obey
+// Newton's third law, F(b1←b2) = -F(b2←b1). Forces not implementing it are
+// taken as antisymmetric: solvers may evaluate each pair once and apply the
+// opposite force to the other body.
+type Antisymmetric interface {
+	IsAntisymmetric() bool
+}
+
+type newtonianForce struct{}
+
+type legacyForce struct{}
+
+type yukawa struct {
+	length float64
+}
+
+type mond struct {
+	a0 float64
+}
+
+type coulomb struct {
+	k float64
+}
+
+type sumForce struct {
+	forces []PairForce
+}
+
+// NewNewtonian creates Newton's law of universal gravitation, the System
+// default
+func NewNewtonian() PairForce {
+	return newtonianForce{}
+}
+
+// NewLegacyNewtonian creates the per-axis law of LegacyGrav
+func NewLegacyNewtonian() PairForce {
+	return legacyForce{}
+}
+
+// NewYukawa creates a screened gravity, with potential -G·m1·m2·e^(-r/λ)/r
+func NewYukawa(length float64) (PairForce, error) {
+	if !positive(length) {
+		return nil, fmt.Errorf("invalid screening length: %v", length)
+	}
+
+	return yukawa{length}, nil
+}
+
+// NewMOND creates modified Newtonian dynamics with the simple interpolation
+// function: the Newtonian acceleration gN each body causes is boosted to
+// gN·(1/2 + √(1/4 + a0/gN)). Being applied pairwise, it is only an
+// approximation of MOND, and conserves neither momentum nor energy.
+func NewMOND(a0 float64) (PairForce, error) {
+	if !positive(a0) {
+		return nil, fmt.Errorf("invalid MOND acceleration: %v", a0)
+	}
+
+	return mond{a0}, nil
+}
+
+// NewCoulomb creates the electrostatic force k·q1·q2·r̂/d² between charged
+// bodies; it ignores G, so k must be given in the system units. Test
+// particles carry no charge and never feel it.
+func NewCoulomb(k float64) (PairForce, error) {
+	if !positive(k) {
+		return nil, fmt.Errorf("invalid Coulomb constant: %v", k)
+	}
+
+	return coulomb{k}, nil
+}
+
+// NewSumForce combines pair forces, e.g. gravity and electrostatics
+func NewSumForce(forces ...PairForce) (PairForce, error) {
+	for _, f := range forces {
+		if f == nil {
+			return nil, fmt.Errorf("invalid pair force: %v", f)
+		}
+	}
+
+	return sumForce{forces}, nil
+}
+
+func (newtonianForce) Force(b1, b2 Body, g, softening float64) Point {
+	return newtonian(b1, b2, g, softening)
+}
+
+func (newtonianForce) Potential(b1, b2 Body, g, softening float64) float64 {
+	return -g * b1.GetMass() * b2.GetMass() / softDistance(b1, b2, softening)
+}
+
+func (legacyForce) Force(b1, b2 Body, g, softening float64) Point {
+	return legacyGrav(b1, b2, g, softening)
+}
+
+func (f yukawa) Force(b1, b2 Body, g, softening float64) Point {
+	diff := b1.GetPosition().Diff(b2.GetPosition())
+	s := softDistance(b1, b2, softening)
+	if s == 0 {
+		return NewPoint(0, 0, 0)
+	}
+
+	magnitude := g * b1.GetMass() * b2.GetMass() * math.Exp(-s/f.length) * (1/(s*s) + 1/(f.length*s))
+	return diff.Mul(-magnitude / s)
+}
+
+func (f yukawa) Potential(b1, b2 Body, g, softening float64) float64 {
+	s := softDistance(b1, b2, softening)
+	return -g * b1.GetMass() * b2.GetMass() * math.Exp(-s/f.length) / s
+}
+
+func (f mond) Force(b1, b2 Body, g, softening float64) Point {
+	force := newtonian(b1, b2, g, softening)
+	if b1.GetMass() == 0 {
+		return force
+	}
+
+	gN := force.Magnitude() / b1.GetMass()
+	if gN == 0 {
+		return force
+	}
+	return force.Mul(0.5 + math.Sqrt(0.25+f.a0/gN))
+}
+
+// IsAntisymmetric is false: the boost depends on the mass of the body
+// feeling the force
+func (mond) IsAntisymmetric() bool {
+	return false
+}
+
+func (f coulomb) Force(b1, b2 Body, g, softening float64) Point {
+	diff := b1.GetPosition().Diff(b2.GetPosition())
+	s := softDistance(b1, b2, softening)
+	if s == 0 {
+		return NewPoint(0, 0, 0)
+	}
+	return diff.Mul(f.k * b1.GetCharge() * b2.GetCharge() / (s * s * s))
+}
+
+func (f coulomb) Potential(b1, b2 Body, g, softening float64) float64 {
+	return f.k * b1.GetCharge() * b2.GetCharge() / softDistance(b1, b2, softening)
+}
+
+func (f sumForce) Force(b1, b2 Body, g, softening float64) Point {
+	res := NewPoint(0, 0, 0)
+	for _, force := range f.forces {
+		res = res.Add(force.Force(b1, b2, g, softening))
+	}
+	return res
+}
+
+func (f sumForce) IsAntisymmetric() bool {
+	for _, force := range f.forces {
+		if !isAntisymmetric(force) {
+			return false
+		}
+	}
+	return true
+}
+
+// Potential returns NaN when some of the forces has no potential
+func (f sumForce) Potential(b1, b2 Body, g, softening float64) float64 {
+	var res float64
+	for _, force := range f.forces {
+		p, ok := force.(Potential)
+		if !ok {
+			return math.NaN()
+		}
+		res += p.Potential(b1, b2, g, softening)
+	}
+	return res
+}
+
+func isAntisymmetric(f PairForce) bool {
+	a, ok := f.(Antisymmetric)
+	return !ok || a.IsAntisymmetric()
+}
+
+func softDistance(b1, b2 Body, softening float64) float64 {
+	d := b1.GetPosition().Diff(b2.GetPosition()).Magnitude()
+	return math.Sqrt(d*d + softening*softening)
+}
+
+func (s system) GetPairForce() PairForce {
+	return s.pairForce
+}
+
+func (s *system) SetPairForce(f PairForce) error {
+	if f == nil {
+		return fmt.Errorf("invalid pair force: %v", f)
+	}
+
+	s.pairForce = f
+	return nil
+}
@@ -0,0 +1,94 @@
+package gravity
+
+import (
+	"runtime"
+	"sync"
+)
+
+// workerCount bounds the requested workers by the amount of work; zero or
+// less stands for one worker per available CPU
+func workerCount(workers, n int) int {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > n {
+		workers = n
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	return workers
+}
+
+// parallelFor runs fn for every index below n on workers goroutines, worker
+// w taking the indices congruent to w, and waits for them all; a single
+// worker runs in the calling goroutine
+func parallelFor(n, workers int, fn func(worker, i int)) {
+	if workers == 1 {
+		for i := 0; i < n; i++ {
+			fn(0, i)
+		}
+		return
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < n; i += workers {
+				fn(w, i)
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+// workerTask is work split among the goroutines of a workerPool
+type workerTask interface {
+	runWorker(worker, workers int)
+}
+
+type workerJob struct {
+	task            workerTask
+	worker, workers int
+}
+
+// workerPool keeps goroutines waiting for work, so dispatching it neither
+// spawns goroutines nor allocates
+type workerPool struct {
+	size int
+	jobs chan workerJob
+	wg   sync.WaitGroup
+}
+
+func newWorkerPool(size int) *workerPool {
+	p := &workerPool{size: size, jobs: make(chan workerJob)}
+	for w := 0; w < size; w++ {
+		go p.serve()
+	}
+	return p
+}
+
+func (p *workerPool) serve() {
+	for job := range p.jobs {
+		job.task.runWorker(job.worker, job.workers)
+		p.wg.Done()
+	}
+}
+
+// run splits task among workers goroutines of the pool and waits for them
+func (p *workerPool) run(task workerTask, workers int) {
+	p.wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		p.jobs <- workerJob{task, w, workers}
+	}
+	p.wg.Wait()
+}
+
+// close stops the goroutines of the pool, if any
+func (p *workerPool) close() {
+	if p != nil {
+		close(p.jobs)
+	}
+}
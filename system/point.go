@@ -11,6 +11,8 @@ type Point interface {
 	GetY() float64
 	GetZ() float64
 	Magnitude() float64
+	Dot(Point) float64
+	Cross(Point) Point
 	TanXY() Point
 	TanXZ() Point
 	TanYZ() Point
@@ -49,6 +51,18 @@ func (p point) Magnitude() float64 {
 	return math.Sqrt(p.x*p.x + p.y*p.y + p.z*p.z)
 }
 
+func (p point) Dot(other Point) float64 {
+	return p.x*other.GetX() + p.y*other.GetY() + p.z*other.GetZ()
+}
+
+func (p point) Cross(other Point) Point {
+	return point{
+		x: p.y*other.GetZ() - p.z*other.GetY(),
+		y: p.z*other.GetX() - p.x*other.GetZ(),
+		z: p.x*other.GetY() - p.y*other.GetX(),
+	}
+}
+
 func (p point) TanXY() Point {
 	a := p.Magnitude()
 	ang := math.Acos(p.x/a) + (math.Pi / 2)
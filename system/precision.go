@@ -0,0 +1,111 @@
+package gravity
+
+import "fmt"
+
+// Precision selects how the Euler, leapfrog, Velocity Verlet and RK4
+// integrators accumulate position and velocity increments. Long runs lose
+// the low bits of tiny increments added to large values; the extended modes
+// keep them in a per-body compensation term carried from step to step,
+// which is dropped whenever a body is moved or accelerated from outside the
+// integrator.
+type Precision int
+
+const (
+	// DoublePrecision plain float64 sums, the System default
+	DoublePrecision Precision = iota
+	// CompensatedPrecision Kahan–Neumaier compensated sums of the float64
+	// increments
+	CompensatedPrecision
+	// DoubleDoublePrecision double-double accumulation, about 106 bits,
+	// increments included
+	DoubleDoublePrecision
+)
+
+func (p Precision) String() string {
+	switch p {
+	case DoublePrecision:
+		return "double"
+	case CompensatedPrecision:
+		return "compensated"
+	case DoubleDoublePrecision:
+		return "double-double"
+	}
+	return fmt.Sprintf("Precision(%d)", int(p))
+}
+
+func (s system) GetPrecision() Precision {
+	return s.state.precision
+}
+
+// SetPrecision selects how increments accumulate. Only fixed steps of the
+// Euler, leapfrog, Velocity Verlet and RK4 integrators honour the extended
+// modes: Step refuses other integrators, and Advance refuses them all.
+func (s *system) SetPrecision(precision Precision) error {
+	if precision < DoublePrecision || precision > DoubleDoublePrecision {
+		return fmt.Errorf("invalid precision: %v", precision)
+	}
+
+	s.state.precision = precision
+	s.state.resetCompensation()
+	return nil
+}
+
+// checkPrecision returns an error when the precision is extended but the
+// stepping cannot honour it: adaptive, or with an integrator unable to step
+// on the arrays
+func (s system) checkPrecision(adaptive bool) error {
+	if s.state.precision == DoublePrecision {
+		return nil
+	}
+	if _, ok := s.integrator.(stateIntegrator); adaptive || !ok {
+		return fmt.Errorf("%v precision needs fixed Euler, leapfrog, Velocity Verlet or RK4 steps", s.state.precision)
+	}
+	return nil
+}
+
+// product returns v·f, and its rounding error in double-double precision
+func (p Precision) product(v, lo Vector, f float64) (Vector, Vector) {
+	if p != DoubleDoublePrecision {
+		return v.Mul(f), Vector{}
+	}
+
+	var res, err Vector
+	res.X, err.X = twoProduct(v.X, f)
+	res.Y, err.Y = twoProduct(v.Y, f)
+	res.Z, err.Z = twoProduct(v.Z, f)
+	return res, err.Add(lo.Mul(f))
+}
+
+// accumulate adds inc + incLo to the pair hi + lo, keeping it normalised
+func accumulate(hi, lo *Vector, inc, incLo Vector) {
+	hi.X, lo.X = accumulateFloat(hi.X, lo.X, inc.X, incLo.X)
+	hi.Y, lo.Y = accumulateFloat(hi.Y, lo.Y, inc.Y, incLo.Y)
+	hi.Z, lo.Z = accumulateFloat(hi.Z, lo.Z, inc.Z, incLo.Z)
+}
+
+func accumulateFloat(hi, lo, inc, incLo float64) (float64, float64) {
+	s, e := twoSum(hi, inc)
+	return twoSum(s, e+lo+incLo)
+}
+
+// twoSum returns a + b and its exact rounding error (Knuth)
+func twoSum(a, b float64) (float64, float64) {
+	s := a + b
+	bb := s - a
+	return s, (a - (s - bb)) + (b - bb)
+}
+
+// twoProduct returns a·b and its exact rounding error, by Dekker's
+// splitting; the conversions keep the compiler from fusing operations
+func twoProduct(a, b float64) (float64, float64) {
+	p := float64(a * b)
+	ah, al := split(a)
+	bh, bl := split(b)
+	return p, ((float64(ah*bh) - p) + float64(ah*bl) + float64(al*bh)) + float64(al*bl)
+}
+
+func split(a float64) (float64, float64) {
+	c := float64(134217729 * a) // 2²⁷ + 1
+	h := c - (c - a)
+	return h, a - h
+}
@@ -0,0 +1,47 @@
+package gravity
+
+// SpeedOfLight in SI
+const SpeedOfLight = 299792458.0
+
+func (s system) GetPostNewtonian() Body {
+	return s.central
+}
+
+// SetPostNewtonian enables the first post-Newtonian correction for bodies
+// orbiting a dominant central mass, in the test particle limit:
+//
+//	a = GM/(c²r³)·[(4GM/r - v²)·r + 4(r·v)·v]
+//
+// where r and v are relative to the central body, which is not corrected
+// itself, so momentum is only approximately conserved. A nil central body
+// disables the correction.
+func (s *system) SetPostNewtonian(central Body) {
+	s.central = central
+}
+
+// postNewtonian adds the 1PN correction to the accelerations
+func (s system) postNewtonian(bodies []Body, acc []Point) {
+	central := s.central
+	if central == nil || s.bodies[central.GetName()] != central {
+		return
+	}
+
+	c := Convert(SpeedOfLight, DimVelocity, SI, s.units)
+	gm := s.units.G() * central.GetMass()
+	for i, b := range bodies {
+		if b == central {
+			continue
+		}
+
+		r := b.GetPosition().Diff(central.GetPosition())
+		v := b.GetVelocity().Diff(central.GetVelocity())
+		d := r.Magnitude()
+		if d == 0 {
+			continue
+		}
+
+		f := gm / (c * c * d * d * d)
+		correction := r.Mul(f * (4*gm/d - v.Dot(v))).Add(v.Mul(f * 4 * r.Dot(v)))
+		acc[i] = acc[i].Add(correction)
+	}
+}
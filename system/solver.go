@@ -0,0 +1,113 @@
+package gravity
+
+import "fmt"
+
+// GravFunc computes the force b1 suffers from b2; the System hands solvers
+// one that already applies its pair force, units and softening length, so
+// solvers must get every pairwise force through it. Solvers may call it
+// from several goroutines at once.
+type GravFunc func(b1, b2 Body) Point
+
+// Solver computes the acceleration of each body due to the others; test
+// particles feel gravity but do not exert it
+type Solver interface {
+	Accelerations(bodies []Body, grav GravFunc) []Point
+}
+
+// fixedAccumulators is how many accumulators deterministic reductions use,
+// whatever the number of workers
+const fixedAccumulators = 16
+
+type pairwise struct {
+	workers         int
+	accumulators    int
+	asymmetricForce bool
+}
+
+// deterministicSolver is implemented by solvers able to reduce forces in an
+// order independent of their number of workers
+type deterministicSolver interface {
+	deterministic() Solver
+}
+
+// asymmetricSolver is implemented by solvers relying on Newton's third law,
+// which must evaluate both forces of a pair for other laws
+type asymmetricSolver interface {
+	asymmetric() Solver
+}
+
+// NewPairwiseSolver creates the exact solver, the System default: its cost
+// is O(M·N) for M massive bodies out of N, spread over one worker per CPU
+func NewPairwiseSolver() Solver {
+	return pairwise{}
+}
+
+// NewPairwiseSolverWithWorkers creates the exact solver running on at most
+// the given number of workers; a single worker computes serially
+func NewPairwiseSolverWithWorkers(workers int) (Solver, error) {
+	if workers < 1 {
+		return nil, fmt.Errorf("invalid worker count: %v", workers)
+	}
+
+	return pairwise{workers: workers}, nil
+}
+
+func (s pairwise) Accelerations(bodies []Body, grav GravFunc) []Point {
+	res := make([]Point, len(bodies))
+	massive, massless := partitionMassive(bodies)
+	sources := make([]Body, len(massive))
+	for k, i := range massive {
+		sources[k] = bodies[i]
+	}
+
+	forces := interactBodies(sources, grav, s.workers, s.accumulators, s.asymmetricForce)
+	for k, i := range massive {
+		res[i] = forces[k].Mul(1 / bodies[i].GetMass())
+	}
+
+	parallelFor(len(massless), workerCount(s.workers, len(massless)), func(_, k int) {
+		i := massless[k]
+		probe := unitMass(bodies[i])
+		acc := NewPoint(0, 0, 0)
+		for _, source := range sources {
+			acc = acc.Add(grav(probe, source))
+		}
+		res[i] = acc
+	})
+	return res
+}
+
+func (s pairwise) deterministic() Solver {
+	s.accumulators = fixedAccumulators
+	return s
+}
+
+func (s pairwise) asymmetric() Solver {
+	s.asymmetricForce = true
+	return s
+}
+
+// partitionMassive returns the indices of massive bodies and test particles
+func partitionMassive(bodies []Body) ([]int, []int) {
+	var massive, massless []int
+	for i, b := range bodies {
+		if b.GetMass() == 0 {
+			massless = append(massless, i)
+		} else {
+			massive = append(massive, i)
+		}
+	}
+	return massive, massless
+}
+
+// unitMass returns a neutral copy of b weighting 1, so forces on it are
+// accelerations; it stands for test particles wherever a force is computed
+func unitMass(b Body) Body {
+	return &body{
+		name:     b.GetName(),
+		mass:     1,
+		position: b.GetPositionVector(),
+		velocity: b.GetVelocityVector(),
+		radius:   b.GetRadius(),
+	}
+}
@@ -0,0 +1,359 @@
+package gravity
+
+import (
+	"math"
+	"sort"
+)
+
+// state keeps the bodies of a system as structure of arrays, ordered by
+// name. Bodies remain the authoritative data: built-in integrators gather
+// them into the arrays, integrate plain values and scatter the result back,
+// reusing every buffer. With plain Newtonian pairwise forces, accelerations
+// are computed on the arrays too, so a steady-state Step allocates nothing;
+// otherwise accel computes them through the Body interface.
+type state struct {
+	bodies   []Body
+	mass     []float64
+	pinned   []bool
+	pos, vel []Vector
+	acc      []Vector
+	massive  []int
+	massless []int
+	sources  []int // massive bodies, while looking for pairs
+	accel    AccelFunc
+
+	// compensation terms of the extended precisions
+	precision    Precision
+	posLo, velLo []Vector
+
+	// integrator scratch
+	x0, v0     []Vector
+	x0Lo, v0Lo []Vector
+	kx, kv     [4][]Vector
+
+	// force evaluation
+	g            float64
+	eps2         float64
+	workers      int
+	accumulators int        // zero for one per worker
+	sums         [][]Vector // the accumulators in use
+	pool         *workerPool
+}
+
+// stateIntegrator is implemented by integrators able to advance the
+// system arrays directly
+type stateIntegrator interface {
+	integrateState(st *state, t, dt float64)
+}
+
+// sync rebuilds the body order when the body set changed since last call
+func (st *state) sync(bodies map[string]Body) {
+	if len(st.bodies) == len(bodies) {
+		same := true
+		for _, b := range st.bodies {
+			if bodies[b.GetName()] != b {
+				same = false
+				break
+			}
+		}
+		if same {
+			return
+		}
+	}
+
+	// keep the last state of the remaining bodies, with compensation
+	previous := make(map[Body][4]Vector, len(st.bodies))
+	for i, b := range st.bodies {
+		previous[b] = [4]Vector{st.pos[i], st.vel[i], st.posLo[i], st.velLo[i]}
+	}
+
+	st.bodies = st.bodies[:0]
+	for _, b := range bodies {
+		st.bodies = append(st.bodies, b)
+	}
+	sort.Slice(st.bodies, func(i, j int) bool {
+		return st.bodies[i].GetName() < st.bodies[j].GetName()
+	})
+
+	n := len(st.bodies)
+	if cap(st.mass) < n {
+		st.mass = make([]float64, n)
+		st.pinned = make([]bool, n)
+		st.massive = make([]int, 0, n)
+		st.massless = make([]int, 0, n)
+	}
+	st.mass = st.mass[:n]
+	st.pinned = st.pinned[:n]
+	st.pos = resizeVectors(st.pos, n)
+	st.vel = resizeVectors(st.vel, n)
+	st.acc = resizeVectors(st.acc, n)
+	st.posLo = resizeVectors(st.posLo, n)
+	st.velLo = resizeVectors(st.velLo, n)
+	st.x0 = resizeVectors(st.x0, n)
+	st.v0 = resizeVectors(st.v0, n)
+	st.x0Lo = resizeVectors(st.x0Lo, n)
+	st.v0Lo = resizeVectors(st.v0Lo, n)
+	for k := range st.kx {
+		st.kx[k] = resizeVectors(st.kx[k], n)
+		st.kv[k] = resizeVectors(st.kv[k], n)
+	}
+	for w := range st.sums {
+		st.sums[w] = resizeVectors(st.sums[w], n)
+	}
+
+	for i, b := range st.bodies {
+		last := previous[b]
+		st.pos[i], st.vel[i], st.posLo[i], st.velLo[i] = last[0], last[1], last[2], last[3]
+	}
+}
+
+func (st *state) resetCompensation() {
+	for i := range st.posLo {
+		st.posLo[i] = Vector{}
+		st.velLo[i] = Vector{}
+	}
+}
+
+func resizeVectors(v []Vector, n int) []Vector {
+	if cap(v) < n {
+		return make([]Vector, n)
+	}
+	return v[:n]
+}
+
+// gather copies the state of the bodies into the arrays, dropping the
+// compensation of bodies changed since the last scatter
+func (st *state) gather() {
+	st.massive = st.massive[:0]
+	st.massless = st.massless[:0]
+	for i, b := range st.bodies {
+		st.mass[i] = b.GetMass()
+		st.pinned[i] = b.IsPinned()
+		if pos := b.GetPositionVector(); pos != st.pos[i] {
+			st.pos[i] = pos
+			st.posLo[i] = Vector{}
+		}
+		if vel := b.GetVelocityVector(); vel != st.vel[i] {
+			st.vel[i] = vel
+			st.velLo[i] = Vector{}
+		}
+		if st.mass[i] == 0 {
+			st.massless = append(st.massless, i)
+		} else {
+			st.massive = append(st.massive, i)
+		}
+	}
+}
+
+// scatter copies the arrays back into the bodies
+func (st *state) scatter() {
+	for i, b := range st.bodies {
+		if !st.pinned[i] {
+			b.SetPositionVector(st.pos[i])
+			b.SetVelocityVector(st.vel[i])
+		}
+	}
+}
+
+func (st *state) kick(dt float64) {
+	for i := range st.vel {
+		if st.pinned[i] {
+			continue
+		}
+		if st.precision == DoublePrecision {
+			st.vel[i] = st.vel[i].Add(st.acc[i].Mul(dt))
+		} else {
+			inc, incLo := st.precision.product(st.acc[i], Vector{}, dt)
+			accumulate(&st.vel[i], &st.velLo[i], inc, incLo)
+		}
+	}
+}
+
+func (st *state) drift(dt float64) {
+	for i := range st.pos {
+		if st.pinned[i] {
+			continue
+		}
+		if st.precision == DoublePrecision {
+			st.pos[i] = st.pos[i].Add(st.vel[i].Mul(dt))
+		} else {
+			inc, incLo := st.precision.product(st.vel[i], st.velLo[i], dt)
+			accumulate(&st.pos[i], &st.posLo[i], inc, incLo)
+		}
+	}
+}
+
+// accelerate fills acc for the time t. Plain Newtonian accelerations are
+// computed on the arrays: rows of massive pairs come first, then one row
+// per test particle; rows are dealt to accumulators and reduced in order,
+// as interactBodies does.
+func (st *state) accelerate(t float64) {
+	if st.accel != nil {
+		st.scatter()
+		for i, acc := range st.accel(st.bodies, t) {
+			st.acc[i] = ToVector(acc)
+		}
+		return
+	}
+
+	rows := len(st.massive) + len(st.massless) - 1
+	if len(st.massive) == 0 {
+		rows = 0
+	}
+	accumulators := st.accumulators
+	if accumulators <= 0 {
+		accumulators = workerCount(st.workers, rows)
+	}
+	for len(st.sums) < accumulators {
+		st.sums = append(st.sums, make([]Vector, len(st.bodies)))
+	}
+	st.sums = st.sums[:accumulators]
+
+	if workers := workerCount(st.workers, accumulators); workers == 1 {
+		st.runWorker(0, 1)
+	} else {
+		if st.pool == nil || st.pool.size < workers {
+			st.pool.close()
+			st.pool = newWorkerPool(workers)
+		}
+		st.pool.run(st, workers)
+	}
+
+	for _, i := range st.massive {
+		var acc Vector
+		for _, sum := range st.sums {
+			acc = acc.Add(sum[i])
+		}
+		st.acc[i] = acc
+	}
+	for i, pinned := range st.pinned {
+		if pinned {
+			st.acc[i] = Vector{}
+		}
+	}
+	if len(st.massive) == 0 {
+		// no row is left, and nothing pulls on test particles
+		for _, i := range st.massless {
+			st.acc[i] = Vector{}
+		}
+	}
+}
+
+// runWorker fills the accumulators congruent to worker
+func (st *state) runWorker(worker, workers int) {
+	for a := worker; a < len(st.sums); a += workers {
+		st.forceRows(a, len(st.sums))
+	}
+}
+
+// forceRows computes the rows congruent to the given accumulator
+func (st *state) forceRows(accumulator, accumulators int) {
+	sum := st.sums[accumulator]
+	for i := range sum {
+		sum[i] = Vector{}
+	}
+
+	triangle := len(st.massive) - 1
+	if triangle < 0 {
+		return
+	}
+	rows := triangle + len(st.massless)
+
+	for r := accumulator; r < rows; r += accumulators {
+		if r < triangle {
+			i := st.massive[r]
+			for _, j := range st.massive[r+1:] {
+				d, f := st.pull(i, j)
+				sum[i] = sum[i].Add(d.Mul(f * st.mass[j]))
+				sum[j] = sum[j].Sub(d.Mul(f * st.mass[i]))
+			}
+			continue
+		}
+
+		i := st.massless[r-triangle]
+		var acc Vector
+		for _, j := range st.massive {
+			d, f := st.pull(i, j)
+			acc = acc.Add(d.Mul(f * st.mass[j]))
+		}
+		st.acc[i] = acc
+	}
+}
+
+// pull returns the separation from i to j and G/(d²+ε²)^(3/2)
+func (st *state) pull(i, j int) (Vector, float64) {
+	d := st.pos[j].Sub(st.pos[i])
+	d2 := d.Dot(d) + st.eps2
+	if d2 == 0 {
+		return d, 0
+	}
+	return d, st.g / (d2 * math.Sqrt(d2))
+}
+
+func (euler) integrateState(st *state, t, dt float64) {
+	st.accelerate(t)
+	st.kick(dt)
+	st.drift(dt)
+}
+
+func (leapfrog) integrateState(st *state, t, dt float64) {
+	st.drift(dt / 2)
+	st.accelerate(t + dt/2)
+	st.kick(dt)
+	st.drift(dt / 2)
+}
+
+func (verlet) integrateState(st *state, t, dt float64) {
+	st.accelerate(t)
+	st.kick(dt / 2)
+	st.drift(dt)
+	st.accelerate(t + dt)
+	st.kick(dt / 2)
+}
+
+// integrateState evaluates the stages from the leading part of positions
+// and velocities; the compensation only carries the final update
+func (rk4) integrateState(st *state, t, dt float64) {
+	copy(st.x0, st.pos)
+	copy(st.v0, st.vel)
+	copy(st.x0Lo, st.posLo)
+	copy(st.v0Lo, st.velLo)
+	steps := [4]float64{dt / 2, dt / 2, dt, 0}
+	times := [4]float64{t, t + dt/2, t + dt/2, t + dt}
+
+	for k, h := range steps {
+		copy(st.kx[k], st.vel)
+		st.accelerate(times[k])
+		copy(st.kv[k], st.acc)
+		if k == 3 {
+			break
+		}
+		for i := range st.pos {
+			if !st.pinned[i] {
+				st.pos[i] = st.x0[i].Add(st.kx[k][i].Mul(h))
+				st.vel[i] = st.v0[i].Add(st.kv[k][i].Mul(h))
+			}
+		}
+	}
+
+	kx, kv := st.kx, st.kv
+	for i := range st.pos {
+		if st.pinned[i] {
+			continue
+		}
+		dx := kx[0][i].Add(kx[1][i].Mul(2)).Add(kx[2][i].Mul(2)).Add(kx[3][i])
+		dv := kv[0][i].Add(kv[1][i].Mul(2)).Add(kv[2][i].Mul(2)).Add(kv[3][i])
+		if st.precision == DoublePrecision {
+			st.pos[i] = st.x0[i].Add(dx.Mul(dt / 6))
+			st.vel[i] = st.v0[i].Add(dv.Mul(dt / 6))
+			continue
+		}
+
+		st.pos[i], st.posLo[i] = st.x0[i], st.x0Lo[i]
+		st.vel[i], st.velLo[i] = st.v0[i], st.v0Lo[i]
+		inc, incLo := st.precision.product(dx, Vector{}, dt/6)
+		accumulate(&st.pos[i], &st.posLo[i], inc, incLo)
+		inc, incLo = st.precision.product(dv, Vector{}, dt/6)
+		accumulate(&st.vel[i], &st.velLo[i], inc, incLo)
+	}
+}
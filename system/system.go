@@ -1,8 +1,12 @@
 package gravity
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+	"runtime"
+)
 
-// G universal gravitational constant
+// G universal gravitational constant, in SI
 const G = 6.67408e-11
 
 // System represents a gravitational system
@@ -13,18 +17,115 @@ type System interface {
 	AddBody(Body) error
 	RemoveBody(Body) bool
 	Step(float64) error
+	Advance(target, tolerance float64) (AdvanceReport, error)
+	GetTime() float64
+	GetIntegrator() Integrator
+	SetIntegrator(Integrator) error
+	GetSolver() Solver
+	SetSolver(Solver) error
+	IsLegacyGravity() bool
+	SetLegacyGravity(bool)
+	GetPairForce() PairForce
+	SetPairForce(PairForce) error
+	IsDeterministic() bool
+	SetDeterministic(bool)
+	GetPrecision() Precision
+	SetPrecision(Precision) error
+	GetSoftening() float64
+	SetSoftening(float64) error
+	GetUnits() Units
+	SetUnits(Units) error
+	GetPostNewtonian() Body
+	SetPostNewtonian(Body)
+	GetForces() []ExternalForce
+	AddForce(ExternalForce) error
+	RemoveForce(ExternalForce) bool
+	GetMerges() []Merge
+	GetEscapePolicy() EscapePolicy
+	SetEscapePolicy(EscapePolicy) error
+	GetEscapes() []Escape
+	Subscribe(EventKind, EventHandler) (int, error)
+	Unsubscribe(int) bool
+	GetEncounterDistance() float64
+	SetEncounterDistance(float64) error
 	TotalMass() float64
+	KineticEnergy() float64
+	PotentialEnergy() float64
+	Momentum() Point
+	AngularMomentum() Point
+	CenterOfMass() Point
+	VirialRatio() float64
+	Diagnose() Diagnostics
+	GetBaseline() Diagnostics
+	ResetBaseline()
+	GetDrift() Drift
+	Recenter()
+	IsAutoRecenter() bool
+	SetAutoRecenter(bool)
+	SetElements(b, primary Body, el Elements) error
+	GetElements(b, primary Body) (Elements, error)
+	SetCircularOrbit(b, primary Body, normal Point) error
+	CircularVelocity(b, primary Body) (float64, error)
+	EscapeVelocity(b, primary Body) (float64, error)
+	OrbitalPeriod(b, primary Body) (float64, error)
+	GetRotatingFrame(primary, secondary Body) (RotatingFrame, error)
+	LagrangePoints(primary, secondary Body) ([5]Point, error)
+	JacobiConstant(b, primary, secondary Body) (float64, error)
+	CoRotatingSystem(primary, secondary Body, particles ...Body) (System, error)
 	String() string
 }
 
 type system struct {
-	status error
-	bodies map[string]Body
+	status        error
+	bodies        map[string]Body
+	state         *state
+	integrator    Integrator
+	solver        Solver
+	pairForce     PairForce
+	deterministic bool
+	softening     float64
+	units         Units
+	central       Body
+	forces        []ExternalForce
+	time          float64
+	adaptiveDt    float64
+	merges        []Merge
+	escapePolicy  EscapePolicy
+	escapes       []Escape
+	escaped       map[Body]bool
+	baseline      *Diagnostics
+	autoRecenter  bool
+
+	// events
+	subscriptions     []subscription
+	lastSubscription  int
+	encounterDistance float64
+	encounters        map[[2]Body]bool
+	approaches        map[Body]approach
+	mergeMark         int
+	escapeMark        int
 }
 
 // NewSystem build a new system
 func NewSystem(args ...Body) (System, error) {
-	s := system{nil, make(map[string]Body)}
+	return NewSystemWithIntegrator(NewEuler(), args...)
+}
+
+// NewSystemWithIntegrator build a new system stepped by the given integrator
+func NewSystemWithIntegrator(integrator Integrator, args ...Body) (System, error) {
+	if integrator == nil {
+		return nil, fmt.Errorf("invalid integrator: %v", integrator)
+	}
+
+	s := system{
+		bodies:     make(map[string]Body),
+		state:      &state{},
+		integrator: integrator,
+		solver:     NewPairwiseSolver(),
+		pairForce:  NewNewtonian(),
+		units:      SI,
+		escaped:    make(map[Body]bool),
+	}
 	for _, b := range args {
 		if name := b.GetName(); s.bodies[name] == nil {
 			s.bodies[name] = b
@@ -32,6 +133,8 @@ func NewSystem(args ...Body) (System, error) {
 			return nil, fmt.Errorf("duplicated body: %v", name)
 		}
 	}
+	// the force worker pool would otherwise outlive the system
+	runtime.SetFinalizer(&s, func(s *system) { s.state.pool.close() })
 	return &s, nil
 }
 
@@ -51,6 +154,9 @@ func (s *system) AddBody(b Body) error {
 	name := b.GetName()
 	if s.bodies[name] == nil {
 		s.bodies[name] = b
+		if s.autoRecenter {
+			s.Recenter()
+		}
 		return nil
 	}
 
@@ -64,6 +170,7 @@ func (s *system) RemoveBody(b Body) bool {
 	}
 
 	delete(s.bodies, name)
+	delete(s.escaped, b)
 	return true
 }
 
@@ -72,21 +179,200 @@ func (s *system) Step(dt float64) error {
 		return s.status
 	}
 
-	buffer := interactBodies(s.bodies)
+	if dt < 0 {
+		s.status = fmt.Errorf("invalid timedelta %v", dt)
+		return s.status
+	}
+
+	unitIntegrator, err := s.unitIntegrator(s.integrator)
+	if err != nil {
+		return err
+	}
+	if err := s.checkPrecision(false); err != nil {
+		return err
+	}
+
+	if s.baseline == nil {
+		s.ResetBaseline()
+	}
+
+	if err := s.resolveCollisions(); err != nil {
+		s.status = err
+		return err
+	}
+
+	if integrator, ok := s.stateIntegrator(); ok {
+		s.state.sync(s.bodies)
+		s.state.gather()
+		integrator.integrateState(s.state, s.time, dt)
+		s.state.scatter()
+		s.state.accel = nil
+	} else if err := unitIntegrator.Integrate(s.bodyList(), s.time, dt, s.accelerations); err != nil {
+		s.status = err
+		return err
+	}
+
+	s.time += dt
+	if err := s.resolveCollisions(); err != nil {
+		s.status = err
+		return err
+	}
+	s.detectEscapes()
+	return s.dispatchEvents()
+}
+
+// stateIntegrator returns the integrator as a stateIntegrator, loading the
+// force settings into the arrays, when the system can be stepped on them:
+// the pairwise solver with plain Newtonian gravity, and neither external
+// forces nor relativity. Extended precisions step any built-in integrator
+// on the arrays, evaluating other forces through the bodies.
+func (s system) stateIntegrator() (stateIntegrator, bool) {
+	integrator, ok := s.integrator.(stateIntegrator)
+	if !ok {
+		return nil, false
+	}
+	solver, pairwise := s.solver.(pairwise)
+	if !pairwise || s.pairForce != NewNewtonian() || len(s.forces) > 0 || s.central != nil {
+		if s.state.precision == DoublePrecision {
+			return nil, false
+		}
+		s.state.accel = s.accelerations
+		return integrator, true
+	}
+
+	s.state.g = s.units.G()
+	s.state.eps2 = s.softening * s.softening
+	s.state.workers = solver.workers
+	s.state.accumulators = solver.accumulators
+	if s.deterministic {
+		s.state.accumulators = fixedAccumulators
+	}
+	return integrator, true
+}
+
+// unitIntegrator returns integrator set up for the system units. Those
+// splitting off Keplerian orbits only remove the plain Newtonian pull of
+// the central body, so they refuse any other gravity.
+func (s system) unitIntegrator(integrator Integrator) (Integrator, error) {
+	switch i := integrator.(type) {
+	case stepDoubling:
+		inner, err := s.unitIntegrator(i.integrator)
+		return stepDoubling{inner}, err
 
-	for b := range buffer {
-		if err := b.Move(dt); err != nil {
-			s.status = err
-			return err
+	case gravityIntegrator:
+		if s.softening != 0 || s.pairForce != NewNewtonian() || s.central != nil {
+			return nil, fmt.Errorf("Keplerian splitting needs unsoftened Newtonian gravity, without relativity")
 		}
+		return i.withG(s.units.G()), nil
+	}
+	return integrator, nil
+}
+
+// bodyList returns the bodies ordered by name, so anything summed over them
+// adds up the same way on every run, despite map ordering
+func (s system) bodyList() []Body {
+	s.state.sync(s.bodies)
+	return append([]Body(nil), s.state.bodies...)
+}
+
+func (s system) GetTime() float64 {
+	return s.time
+}
+
+func (s system) GetIntegrator() Integrator {
+	return s.integrator
+}
+
+func (s *system) SetIntegrator(integrator Integrator) error {
+	if integrator == nil {
+		return fmt.Errorf("invalid integrator: %v", integrator)
 	}
 
+	s.integrator = integrator
 	return nil
 }
 
+func (s system) GetSolver() Solver {
+	return s.solver
+}
+
+func (s *system) SetSolver(solver Solver) error {
+	if solver == nil {
+		return fmt.Errorf("invalid solver: %v", solver)
+	}
+
+	s.solver = solver
+	return nil
+}
+
+func (s system) IsLegacyGravity() bool {
+	return s.pairForce == NewLegacyNewtonian()
+}
+
+// SetLegacyGravity switches the system to LegacyGrav, for scenarios tuned
+// against the per-axis force, or back to Newtonian gravity; replaying them
+// with dt ≠ 1 also takes NewLegacyEuler
+func (s *system) SetLegacyGravity(legacy bool) {
+	if legacy {
+		s.pairForce = NewLegacyNewtonian()
+	} else if s.IsLegacyGravity() {
+		s.pairForce = NewNewtonian()
+	}
+}
+
+func (s system) IsDeterministic() bool {
+	return s.deterministic
+}
+
+// SetDeterministic makes force reductions follow a fixed order, whatever
+// the number of workers: the same inputs then give bit-identical
+// trajectories, serial or parallel. Bodies are always taken by name.
+func (s *system) SetDeterministic(deterministic bool) {
+	s.deterministic = deterministic
+}
+
+func (s system) GetSoftening() float64 {
+	return s.softening
+}
+
+// SetSoftening sets the Plummer softening length ε: every force is computed
+// over d²+ε² instead of d², taming close encounters
+func (s *system) SetSoftening(softening float64) error {
+	if softening < 0 || math.IsNaN(softening) || math.IsInf(softening, 0) {
+		return fmt.Errorf("invalid softening: %v", softening)
+	}
+
+	s.softening = softening
+	return nil
+}
+
+func (s system) accelerations(bodies []Body, t float64) []Point {
+	solver := s.solver
+	if d, ok := solver.(deterministicSolver); ok && s.deterministic {
+		solver = d.deterministic()
+	}
+	if a, ok := solver.(asymmetricSolver); ok && !isAntisymmetric(s.pairForce) {
+		solver = a.asymmetric()
+	}
+	acc := solver.Accelerations(bodies, s.grav)
+	s.postNewtonian(bodies, acc)
+	s.externalForces(bodies, t, acc)
+	for i, b := range bodies {
+		if b.IsPinned() {
+			acc[i] = NewPoint(0, 0, 0)
+		}
+	}
+	return acc
+}
+
+// grav is the GravFunc handed to solvers, honouring the system settings
+func (s system) grav(b1, b2 Body) Point {
+	return s.pairForce.Force(b1, b2, s.units.G(), s.softening)
+}
+
 func (s system) TotalMass() float64 {
 	var mass float64
-	for _, b := range s.bodies {
+	for _, b := range s.bodyList() {
 		mass += b.GetMass()
 	}
 	return mass
@@ -106,61 +392,56 @@ func (s system) String() string {
 	)
 }
 
-func interactBodies(origin map[string]Body) map[Body][]Point {
-
-	length := len(origin)
-	buffer := make(map[Body][]Point, length)
-	bodies := make([]Body, length)
-	i := 0
-	for _, b := range origin {
-		buffer[b] = make([]Point, length-1)
-		bodies[i] = b
-		i++
+// interactBodies returns the net force on each body, evaluating every pair
+// once. Rows of pairs are dealt round-robin to accumulators, which workers
+// fill and which are then reduced in order. There is one accumulator per
+// worker unless a fixed count is given: then the sums do not depend on the
+// number of workers. A single accumulator sums exactly as a serial loop.
+// Asymmetric laws get both forces of each pair evaluated.
+func interactBodies(bodies []Body, grav GravFunc, workers, accumulators int, asymmetric bool) []Point {
+	length := len(bodies)
+	if accumulators <= 0 {
+		accumulators = workerCount(workers, length-1)
 	}
-
-	type indexedPoint struct {
-		index int
-		value Point
+	acc := make([][][3]float64, accumulators)
+	for a := range acc {
+		acc[a] = make([][3]float64, length)
 	}
 
-	setFirst := func(arr []Point, value Point, res chan indexedPoint) {
-		for i, e := range arr {
-			if e == nil {
-				res <- indexedPoint{index: i, value: value}
-				return
+	parallelFor(accumulators, workerCount(workers, accumulators), func(_, a int) {
+		sum := acc[a]
+		for i := a; i < length-1; i += accumulators {
+			b1 := bodies[i]
+			for j := i + 1; j < length; j++ {
+				diff := grav(b1, bodies[j])
+				x, y, z := diff.GetX(), diff.GetY(), diff.GetZ()
+				sum[i][0] += x
+				sum[i][1] += y
+				sum[i][2] += z
+				if asymmetric {
+					diff = grav(bodies[j], b1)
+					sum[j][0] += diff.GetX()
+					sum[j][1] += diff.GetY()
+					sum[j][2] += diff.GetZ()
+					continue
+				}
+				sum[j][0] -= x
+				sum[j][1] -= y
+				sum[j][2] -= z
 			}
 		}
-		res <- indexedPoint{index: -1, value: nil}
-	}
-
-	for i := 0; i < length-1; i++ {
-		b1 := bodies[i]
-		for j := i + 1; j < length; j++ {
-			b2 := bodies[j]
-			diff := b1.Grav(b2)
-			ch1 := make(chan indexedPoint)
-			ch2 := make(chan indexedPoint)
-			go setFirst(buffer[b2], diff.Mul(-1), ch2)
-			go setFirst(buffer[b1], diff, ch1)
-
-			res := <-ch1
-			close(ch1)
-			if res.value != nil {
-				buffer[b1][res.index] = res.value
-			}
-			res = <-ch2
-			close(ch2)
-			if res.value != nil {
-				buffer[b2][res.index] = res.value
-			}
-		}
-	}
+	})
 
-	for b, incs := range buffer {
-		for _, inc := range incs {
-			b.SetInertia(b.GetInertia().Add(inc))
+	forces := make([]Point, length)
+	for i := range forces {
+		var force [3]float64
+		for _, sum := range acc {
+			force[0] += sum[i][0]
+			force[1] += sum[i][1]
+			force[2] += sum[i][2]
 		}
+		forces[i] = NewPoint(force[0], force[1], force[2])
 	}
 
-	return buffer
+	return forces
 }
@@ -0,0 +1,123 @@
+package gravity
+
+import (
+	"fmt"
+	"math"
+)
+
+// Astronomical constants in SI
+const (
+	AU        = 1.495978707e+11
+	SolarMass = 1.98847e+30
+	Day       = 86400.0
+)
+
+// Units represents a unit system by the size of its units in SI: metres per
+// length unit, kilograms per mass unit and seconds per time unit
+type Units struct {
+	Name   string
+	Length float64
+	Mass   float64
+	Time   float64
+}
+
+// Dimension represents a physical quantity as powers of length, mass and time
+type Dimension struct {
+	Length, Mass, Time int
+}
+
+// SI is the International System of Units
+var SI = Units{"SI", 1, 1, 1}
+
+// Astronomical measures lengths in AU, masses in solar masses and times in
+// days
+var Astronomical = Units{"AU/Msun/day", AU, SolarMass, Day}
+
+// Common dimensions
+var (
+	DimLength       = Dimension{1, 0, 0}
+	DimMass         = Dimension{0, 1, 0}
+	DimTime         = Dimension{0, 0, 1}
+	DimVelocity     = Dimension{1, 0, -1}
+	DimAcceleration = Dimension{1, 0, -2}
+	DimMomentum     = Dimension{1, 1, -1}
+	DimForce        = Dimension{1, 1, -2}
+	DimEnergy       = Dimension{2, 1, -2}
+)
+
+// NewNBodyUnits creates units where G = 1, given the mass and length units
+// in SI
+func NewNBodyUnits(mass, length float64) (Units, error) {
+	if !positive(mass) || !positive(length) {
+		return Units{}, fmt.Errorf("invalid N-body scales: %vKg, %vm", mass, length)
+	}
+
+	return Units{
+		Name:   "N-body",
+		Length: length,
+		Mass:   mass,
+		Time:   math.Sqrt(length * length * length / (G * mass)),
+	}, nil
+}
+
+// NewHenonUnits creates the standard N-body units by Hénon, where G = 1,
+// the total mass is 1 and the total energy is -1/4, given the total mass and
+// energy in SI
+func NewHenonUnits(mass, energy float64) (Units, error) {
+	if energy >= 0 || math.IsNaN(energy) || math.IsInf(energy, 0) {
+		return Units{}, fmt.Errorf("invalid energy for Hénon units: %vJ", energy)
+	}
+
+	u, err := NewNBodyUnits(mass, -G*mass*mass/(4*energy))
+	u.Name = "Hénon"
+	return u, err
+}
+
+// G returns the gravitational constant expressed in u
+func (u Units) G() float64 {
+	return G * u.Mass * u.Time * u.Time / (u.Length * u.Length * u.Length)
+}
+
+// Factor returns the size in SI of the unit of d in u
+func (u Units) Factor(d Dimension) float64 {
+	return math.Pow(u.Length, float64(d.Length)) *
+		math.Pow(u.Mass, float64(d.Mass)) *
+		math.Pow(u.Time, float64(d.Time))
+}
+
+// Convert expresses a value of dimension d given in from as in to
+func Convert(value float64, d Dimension, from, to Units) float64 {
+	return value * from.Factor(d) / to.Factor(d)
+}
+
+// ConvertPoint expresses a vector of dimension d given in from as in to
+func ConvertPoint(p Point, d Dimension, from, to Units) Point {
+	return p.Mul(from.Factor(d) / to.Factor(d))
+}
+
+func (u Units) String() string {
+	return u.Name
+}
+
+func (u Units) valid() bool {
+	return positive(u.Length) && positive(u.Mass) && positive(u.Time)
+}
+
+func positive(value float64) bool {
+	return value > 0 && !math.IsInf(value, 0)
+}
+
+func (s system) GetUnits() Units {
+	return s.units
+}
+
+// SetUnits sets the unit system bodies are expressed in; it does not
+// convert them
+func (s *system) SetUnits(units Units) error {
+	if !units.valid() {
+		return fmt.Errorf("invalid units: %+v", units)
+	}
+
+	s.units = units
+	return nil
+}
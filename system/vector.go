@@ -0,0 +1,54 @@
+package gravity
+
+import (
+	"fmt"
+	"math"
+)
+
+// Vector is a concrete 3D vector: unlike Point, its operations take and
+// return plain values, so they never allocate
+type Vector struct {
+	X, Y, Z float64
+}
+
+// ToVector copies a Point into a Vector
+func ToVector(p Point) Vector {
+	return Vector{p.GetX(), p.GetY(), p.GetZ()}
+}
+
+// Point returns v as a Point
+func (v Vector) Point() Point {
+	return point{v.X, v.Y, v.Z}
+}
+
+func (v Vector) Add(other Vector) Vector {
+	return Vector{v.X + other.X, v.Y + other.Y, v.Z + other.Z}
+}
+
+func (v Vector) Sub(other Vector) Vector {
+	return Vector{v.X - other.X, v.Y - other.Y, v.Z - other.Z}
+}
+
+func (v Vector) Mul(f float64) Vector {
+	return Vector{v.X * f, v.Y * f, v.Z * f}
+}
+
+func (v Vector) Dot(other Vector) float64 {
+	return v.X*other.X + v.Y*other.Y + v.Z*other.Z
+}
+
+func (v Vector) Cross(other Vector) Vector {
+	return Vector{
+		v.Y*other.Z - v.Z*other.Y,
+		v.Z*other.X - v.X*other.Z,
+		v.X*other.Y - v.Y*other.X,
+	}
+}
+
+func (v Vector) Magnitude() float64 {
+	return math.Sqrt(v.X*v.X + v.Y*v.Y + v.Z*v.Z)
+}
+
+func (v Vector) String() string {
+	return fmt.Sprintf("(%v, %v, %v)", v.X, v.Y, v.Z)
+}
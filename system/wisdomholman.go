@@ -0,0 +1,325 @@
+package gravity
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Coordinates selects the canonical coordinates of the Wisdom–Holman
+// splitting
+type Coordinates int
+
+const (
+	// DemocraticHeliocentric heliocentric positions with barycentric
+	// velocities; every planet orbits the central body alone, and the
+	// central body motion becomes a separate jump step
+	DemocraticHeliocentric Coordinates = iota
+	// Jacobi each body orbits the barycentre of the bodies inside it,
+	// ordered by distance from the central body; best for well separated,
+	// hierarchical orbits
+	Jacobi
+)
+
+func (c Coordinates) String() string {
+	switch c {
+	case DemocraticHeliocentric:
+		return "democratic heliocentric"
+	case Jacobi:
+		return "Jacobi"
+	}
+	return fmt.Sprintf("Coordinates(%d)", int(c))
+}
+
+// gravityIntegrator is implemented by integrators splitting off Keplerian
+// orbits, which need the gravitational constant of the system units
+type gravityIntegrator interface {
+	withG(g float64) Integrator
+}
+
+type wisdomHolman struct {
+	coordinates Coordinates
+	g           float64
+}
+
+// NewWisdomHolman creates a Wisdom–Holman mixed-variable symplectic
+// integrator for systems dominated by one central mass: the heaviest body.
+// Each step solves the Keplerian orbits around it analytically and only
+// kicks the bodies with the remaining forces, so steps may span a fair
+// fraction of the shortest orbital period. Pinned bodies other than the
+// central one stay as fixed perturbers. External forces are kicks too, but
+// the gravity must be plain Newtonian, without softening nor relativity:
+// systems refuse to step otherwise.
+//
+// Used outside a System, it takes G in SI.
+func NewWisdomHolman(coordinates Coordinates) (Integrator, error) {
+	if coordinates < DemocraticHeliocentric || coordinates > Jacobi {
+		return nil, fmt.Errorf("invalid coordinates: %v", coordinates)
+	}
+
+	return wisdomHolman{coordinates, G}, nil
+}
+
+func (wh wisdomHolman) withG(g float64) Integrator {
+	wh.g = g
+	return wh
+}
+
+// Integrate applies kick(dt/2), jump(dt/2), Kepler drift(dt), jump(dt/2)
+// and kick(dt/2), where kicks take the accelerations left once the
+// Keplerian ones are removed, and jumps only exist in democratic
+// heliocentric coordinates
+func (wh wisdomHolman) Integrate(bodies []Body, t, dt float64, accel AccelFunc) error {
+	if dt < 0 {
+		return fmt.Errorf("invalid timedelta %v", dt)
+	}
+
+	frame := wh.newFrame(bodies)
+	if frame == nil {
+		return drift(bodies, dt)
+	}
+
+	frame.kick(accel(bodies, t), dt/2)
+	frame.jump(dt / 2)
+	if err := frame.kepler(dt); err != nil {
+		return err
+	}
+	frame.jump(dt / 2)
+	frame.position = frame.position.Add(frame.velocity.Mul(dt))
+	frame.store()
+
+	frame.kick(accel(bodies, t+dt), dt/2)
+	frame.store()
+	return nil
+}
+
+// whFrame holds the bodies moved by the Wisdom–Holman integrator, the
+// central one first, in the canonical coordinates: for each other body its
+// position and velocity relative to its primary, and the barycentre of
+// them all
+type whFrame struct {
+	coordinates Coordinates
+	bodies      []Body
+	index       []int // of each body in the slice handed to Integrate
+	mass        []float64
+	eta         []float64 // cumulative mass
+	mu          []float64 // gravitational parameter of each orbit
+	pinned      bool      // of the central body, which then stays the frame origin
+
+	position, velocity Vector
+	q, u               []Vector
+}
+
+// newFrame returns the frame around the heaviest body, nil if there is no
+// massive one
+func (wh wisdomHolman) newFrame(bodies []Body) *whFrame {
+	central := -1
+	for i, b := range bodies {
+		if b.GetMass() <= 0 {
+			continue
+		}
+		if central < 0 || b.GetMass() > bodies[central].GetMass() ||
+			b.GetMass() == bodies[central].GetMass() && b.GetName() < bodies[central].GetName() {
+			central = i
+		}
+	}
+	if central < 0 {
+		return nil
+	}
+
+	f := &whFrame{
+		coordinates: wh.coordinates,
+		bodies:      []Body{bodies[central]},
+		index:       []int{central},
+		pinned:      bodies[central].IsPinned(),
+	}
+	// inner bodies first, for Jacobi coordinates
+	origin := bodies[central].GetPositionVector()
+	type orbit struct {
+		index    int
+		distance float64
+	}
+	orbits := []orbit{}
+	for i, b := range bodies {
+		if i != central && !b.IsPinned() {
+			orbits = append(orbits, orbit{i, b.GetPositionVector().Sub(origin).Magnitude()})
+		}
+	}
+	sort.SliceStable(orbits, func(i, j int) bool {
+		return orbits[i].distance < orbits[j].distance
+	})
+	for _, o := range orbits {
+		f.bodies = append(f.bodies, bodies[o.index])
+		f.index = append(f.index, o.index)
+	}
+
+	n := len(f.bodies)
+	f.mass = make([]float64, n)
+	f.eta = make([]float64, n)
+	f.mu = make([]float64, n)
+	f.q = make([]Vector, n)
+	f.u = make([]Vector, n)
+	for k, b := range f.bodies {
+		f.mass[k] = b.GetMass()
+		f.eta[k] = f.mass[k]
+		if k > 0 {
+			f.eta[k] += f.eta[k-1]
+		}
+	}
+	m0 := f.mass[0]
+	for k := 1; k < n; k++ {
+		f.mu[k] = wh.g * m0
+		if f.coordinates == Jacobi && !f.pinned {
+			f.mu[k] = wh.g * m0 * f.eta[k] / f.eta[k-1]
+		}
+	}
+
+	f.load()
+	return f
+}
+
+// load converts the body states into the frame coordinates
+func (f *whFrame) load() {
+	origin := f.bodies[0].GetPositionVector()
+	if f.pinned {
+		for k := 1; k < len(f.bodies); k++ {
+			f.q[k] = f.bodies[k].GetPositionVector().Sub(origin)
+			f.u[k] = f.bodies[k].GetVelocityVector()
+		}
+		return
+	}
+
+	if f.coordinates == Jacobi {
+		// running barycentre of the inner bodies
+		com := origin
+		comVelocity := f.bodies[0].GetVelocityVector()
+		for k := 1; k < len(f.bodies); k++ {
+			x, v := f.bodies[k].GetPositionVector(), f.bodies[k].GetVelocityVector()
+			f.q[k] = x.Sub(com)
+			f.u[k] = v.Sub(comVelocity)
+			com = com.Add(f.q[k].Mul(f.mass[k] / f.eta[k]))
+			comVelocity = comVelocity.Add(f.u[k].Mul(f.mass[k] / f.eta[k]))
+		}
+		f.position, f.velocity = com, comVelocity
+		return
+	}
+
+	var moment, momentum Vector
+	for k, b := range f.bodies {
+		moment = moment.Add(b.GetPositionVector().Mul(f.mass[k]))
+		momentum = momentum.Add(b.GetVelocityVector().Mul(f.mass[k]))
+	}
+	total := f.eta[len(f.eta)-1]
+	f.position = moment.Mul(1 / total)
+	f.velocity = momentum.Mul(1 / total)
+	for k := 1; k < len(f.bodies); k++ {
+		f.q[k] = f.bodies[k].GetPositionVector().Sub(origin)
+		f.u[k] = f.bodies[k].GetVelocityVector().Sub(f.velocity)
+	}
+}
+
+// store converts the frame coordinates back into the body states
+func (f *whFrame) store() {
+	n := len(f.bodies)
+	if f.pinned {
+		origin := f.bodies[0].GetPositionVector()
+		for k := 1; k < n; k++ {
+			f.bodies[k].SetPositionVector(origin.Add(f.q[k]))
+			f.bodies[k].SetVelocityVector(f.u[k])
+		}
+		return
+	}
+
+	if f.coordinates == Jacobi {
+		com, comVelocity := f.position, f.velocity
+		for k := n - 1; k > 0; k-- {
+			com = com.Sub(f.q[k].Mul(f.mass[k] / f.eta[k]))
+			comVelocity = comVelocity.Sub(f.u[k].Mul(f.mass[k] / f.eta[k]))
+			f.bodies[k].SetPositionVector(com.Add(f.q[k]))
+			f.bodies[k].SetVelocityVector(comVelocity.Add(f.u[k]))
+		}
+		f.bodies[0].SetPositionVector(com)
+		f.bodies[0].SetVelocityVector(comVelocity)
+		return
+	}
+
+	var moment, momentum Vector
+	for k := 1; k < n; k++ {
+		moment = moment.Add(f.q[k].Mul(f.mass[k]))
+		momentum = momentum.Add(f.u[k].Mul(f.mass[k]))
+	}
+	origin := f.position.Sub(moment.Mul(1 / f.eta[n-1]))
+	f.bodies[0].SetPositionVector(origin)
+	f.bodies[0].SetVelocityVector(f.velocity.Sub(momentum.Mul(1 / f.mass[0])))
+	for k := 1; k < n; k++ {
+		f.bodies[k].SetPositionVector(origin.Add(f.q[k]))
+		f.bodies[k].SetVelocityVector(f.velocity.Add(f.u[k]))
+	}
+}
+
+// kick applies the accelerations, indexed as the bodies handed to
+// Integrate, less the Keplerian ones, for h
+func (f *whFrame) kick(acc []Point, h float64) {
+	n := len(f.bodies)
+	a := make([]Vector, n)
+	for k, i := range f.index {
+		a[k] = ToVector(acc[i])
+	}
+
+	// the barycentre of the inner bodies accelerates as their mass-weighted
+	// mean; a pinned central body never does
+	var inner, sum Vector
+	for k := 0; k < n; k++ {
+		if k > 0 {
+			var kepler Vector
+			if r2 := f.q[k].Dot(f.q[k]); r2 > 0 {
+				kepler = f.q[k].Mul(f.mu[k] / (r2 * math.Sqrt(r2)))
+			}
+			if f.coordinates == Jacobi {
+				f.u[k] = f.u[k].Add(a[k].Sub(inner).Add(kepler).Mul(h))
+			} else {
+				f.u[k] = f.u[k].Add(a[k].Add(kepler).Mul(h))
+			}
+		}
+		if !f.pinned {
+			sum = sum.Add(a[k].Mul(f.mass[k]))
+			inner = sum.Mul(1 / f.eta[k])
+		}
+	}
+
+	f.velocity = f.velocity.Add(inner.Mul(h))
+	if f.coordinates == DemocraticHeliocentric {
+		for k := 1; k < n; k++ {
+			f.u[k] = f.u[k].Sub(inner.Mul(h))
+		}
+	}
+}
+
+// jump moves the planets along with the central body, which in democratic
+// heliocentric coordinates carries the opposite of their momenta
+func (f *whFrame) jump(h float64) {
+	if f.coordinates != DemocraticHeliocentric || f.pinned {
+		return
+	}
+
+	var momentum Vector
+	for k := 1; k < len(f.bodies); k++ {
+		momentum = momentum.Add(f.u[k].Mul(f.mass[k]))
+	}
+	shift := momentum.Mul(h / f.mass[0])
+	for k := 1; k < len(f.bodies); k++ {
+		f.q[k] = f.q[k].Add(shift)
+	}
+}
+
+// kepler moves every body along its Keplerian orbit for h
+func (f *whFrame) kepler(h float64) error {
+	for k := 1; k < len(f.bodies); k++ {
+		q, u, err := keplerDrift(f.mu[k], f.q[k], f.u[k], h)
+		if err != nil {
+			return fmt.Errorf("%v: %v", f.bodies[k].GetName(), err)
+		}
+		f.q[k], f.u[k] = q, u
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	gravity "github.com/cacilhas/gravity/system"
+)
+
+func TestAdaptive(t *testing.T) {
+	// eccentric orbit, GM ≈ 10 and circular speed ≈ 1 at distance 10
+	eccentric := func(integrator gravity.Integrator) (gravity.System, gravity.Body) {
+		body1, _ := gravity.NewBody("Sun", 1.5e+11, 0, 0, 0)
+		body2, _ := gravity.NewBody("Probe", 1, 10, 0, 0)
+		body2.SetInertia(gravity.NewPoint(0, 0.5, 0))
+		system, _ := gravity.NewSystemWithIntegrator(integrator, body1, body2)
+		return system, body2
+	}
+
+	energy := func(system gravity.System) float64 {
+		sun := system.GetBody("Sun")
+		probe := system.GetBody("Probe")
+		d := sun.GetPosition().Diff(probe.GetPosition()).Magnitude()
+		p1 := sun.GetInertia().Magnitude()
+		p2 := probe.GetInertia().Magnitude()
+		return p1*p1/(2*sun.GetMass()) + p2*p2/(2*probe.GetMass()) -
+			gravity.G*sun.GetMass()*probe.GetMass()/d
+	}
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		tests := []struct {
+			name              string
+			target, tolerance float64
+		}{
+			{"null tolerance", 1, 0},
+			{"NaN tolerance", 1, math.NaN()},
+			{"infinite tolerance", 1, math.Inf(1)},
+			{"past target", -1, 1e-6},
+			{"NaN target", math.NaN(), 1e-6},
+		}
+		for _, test := range tests {
+			system, _ := gravity.NewSystem()
+			if _, err := system.Advance(test.target, test.tolerance); err == nil {
+				t.Fatalf("[Advance] error not raised for %v", test.name)
+			}
+			if system.Status() == nil {
+				t.Fatalf("[Advance] expected status set for %v", test.name)
+			}
+		}
+	})
+
+	t.Run("non-finite estimate", func(t *testing.T) {
+		for _, estimate := range []float64{math.NaN(), math.Inf(1)} {
+			system, probe := eccentric(nanEstimate{estimate})
+			start := probe.GetPosition()
+			if _, err := system.Advance(1, 1e-6); err == nil {
+				t.Fatalf("[Advance] error not raised for estimate %v", estimate)
+			}
+			if got := probe.GetPosition(); got.Diff(start).Magnitude() != 0 {
+				t.Fatalf("[Advance] expected the step undone, got %v", got)
+			}
+		}
+	})
+
+	t.Run("Dormand-Prince", func(t *testing.T) {
+		system, _ := eccentric(gravity.NewDormandPrince())
+		e0 := energy(system)
+		report, err := system.Advance(50, 1e-9)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var total, smallest, largest float64
+		smallest = math.Inf(1)
+		for i, dt := range report.Steps {
+			total += dt
+			smallest = math.Min(smallest, dt)
+			largest = math.Max(largest, dt)
+			if report.Errors[i] > 1e-9 {
+				t.Fatalf("step %v accepted with error %v", i, report.Errors[i])
+			}
+		}
+
+		tests := []struct {
+			name  string
+			check bool
+		}{
+			{"reaches target", system.GetTime() == 50},
+			{"steps add up", math.Abs(total-50) < 1e-9},
+			{"steps adapt", smallest < largest/2},
+			{"energy kept", math.Abs((energy(system)-e0)/e0) < 1e-6},
+		}
+
+		for _, test := range tests {
+			if !test.check {
+				t.Fatalf("[Advance] %v failed: %+v", test.name, report)
+			}
+		}
+	})
+
+	t.Run("step doubling", func(t *testing.T) {
+		system, _ := eccentric(gravity.NewVelocityVerlet())
+		e0 := energy(system)
+		report, err := system.Advance(20, 1e-6)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got := system.GetTime(); got != 20 {
+			t.Fatalf("expected time 20, got %v", got)
+		}
+
+		if len(report.Steps) < 10 {
+			t.Fatalf("expected subdivided steps, got %v", report.Steps)
+		}
+
+		if got := math.Abs((energy(system) - e0) / e0); got > 1e-3 {
+			t.Fatalf("expected energy drift below 1e-3, got %v", got)
+		}
+	})
+}
+
+// nanEstimate is an Euler integrator reporting a broken error estimate
+type nanEstimate struct {
+	estimate float64
+}
+
+func (i nanEstimate) Integrate(bodies []gravity.Body, t, dt float64, accel gravity.AccelFunc) error {
+	return gravity.NewEuler().Integrate(bodies, t, dt, accel)
+}
+
+func (i nanEstimate) IntegrateWithError(bodies []gravity.Body, t, dt float64, accel gravity.AccelFunc) (float64, error) {
+	return i.estimate, i.Integrate(bodies, t, dt, accel)
+}
+
+func (i nanEstimate) Order() int {
+	return 1
+}
@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"math"
 	"testing"
 
 	gravity "github.com/cacilhas/gravity/system"
@@ -83,4 +84,275 @@ func TestGravityBody(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("#Grav", func(t *testing.T) {
+		body1, _ := gravity.NewBody("Sample 1", 1e+10, 0, 0, 0)
+		body2, _ := gravity.NewBody("Sample 2", 1e+10, 3, 4, 0)
+		force := body1.Grav(body2)
+		f := gravity.G * 1e+10 * 1e+10 / 25
+
+		tests := []struct {
+			name          string
+			expected, got float64
+		}{
+			{"Magnitude", f, force.Magnitude()},
+			{"X", f * 3 / 5, force.GetX()},
+			{"Y", f * 4 / 5, force.GetY()},
+			{"Z", 0, force.GetZ()},
+			{"reaction X", -force.GetX(), body2.Grav(body1).GetX()},
+			{"reaction Y", -force.GetY(), body2.Grav(body1).GetY()},
+		}
+
+		for _, test := range tests {
+			if math.Abs(test.got-test.expected) > 1e-12*f {
+				t.Fatalf(
+					"[Body.Grav %v] expected %v, got %v",
+					test.name, test.expected, test.got,
+				)
+			}
+		}
+	})
+
+	t.Run("LegacyGrav", func(t *testing.T) {
+		body1, _ := gravity.NewBody("Sample 1", 1e+10, 0, 0, 0)
+		body2, _ := gravity.NewBody("Sample 2", 1e+10, 3, 4, 0)
+		force := gravity.LegacyGrav(body1, body2, 0)
+		f := gravity.G * 1e+10 * 1e+10 / 25
+
+		tests := []struct {
+			name          string
+			expected, got float64
+		}{
+			{"X", f, force.GetX()},
+			{"Y", f, force.GetY()},
+			{"Z", 0, force.GetZ()},
+		}
+
+		for _, test := range tests {
+			if test.got != test.expected {
+				t.Fatalf(
+					"[LegacyGrav %v] expected %v, got %v",
+					test.name, test.expected, test.got,
+				)
+			}
+		}
+	})
+
+	t.Run("#SetRadius", func(t *testing.T) {
+		body, _ := gravity.NewBody("Sample", 1, 0, 0, 0)
+
+		if got := body.GetRadius(); got != 0 {
+			t.Fatalf("[Body.GetRadius] expected point mass, got %v", got)
+		}
+
+		if got := body.GetDensity(); got != 0 {
+			t.Fatalf("[Body.GetDensity] expected 0 for point mass, got %v", got)
+		}
+
+		if err := body.SetRadius(-1); err == nil {
+			t.Fatal("[Body.SetRadius] error not raised")
+		}
+
+		body.SetRadius(2)
+		if got := body.GetRadius(); got != 2 {
+			t.Fatalf("[Body.GetRadius] expected 2, got %v", got)
+		}
+	})
+
+	t.Run("#SetDensity", func(t *testing.T) {
+		body, _ := gravity.NewBody("Sample", 4*math.Pi/3, 0, 0, 0)
+
+		if err := body.SetDensity(0); err == nil {
+			t.Fatal("[Body.SetDensity] error not raised")
+		}
+
+		body.SetDensity(1)
+		if got := body.GetRadius(); math.Abs(got-1) > 1e-12 {
+			t.Fatalf("[Body.GetRadius] expected 1, got %v", got)
+		}
+
+		if got := body.GetDensity(); math.Abs(got-1) > 1e-12 {
+			t.Fatalf("[Body.GetDensity] expected 1, got %v", got)
+		}
+	})
+
+	t.Run("#SoftGrav", func(t *testing.T) {
+		body1, _ := gravity.NewBody("Sample 1", 1e+10, 0, 0, 0)
+		body2, _ := gravity.NewBody("Sample 2", 1e+10, 3, 0, 0)
+		body3, _ := gravity.NewBody("Sample 3", 1e+10, 0, 0, 0)
+		f := gravity.G * 1e+10 * 1e+10
+
+		tests := []struct {
+			name          string
+			expected, got float64
+		}{
+			{"ε=0", body1.Grav(body2).GetX(), body1.SoftGrav(body2, 0).GetX()},
+			{"ε=4", f * 3 / 125, body1.SoftGrav(body2, 4).GetX()},
+			{"coincident", 0, body1.SoftGrav(body3, 1).Magnitude()},
+		}
+
+		for _, test := range tests {
+			if math.Abs(test.got-test.expected) > 1e-12*f {
+				t.Fatalf(
+					"[Body.SoftGrav %v] expected %v, got %v",
+					test.name, test.expected, test.got,
+				)
+			}
+		}
+	})
+
+	t.Run("#SetVelocity", func(t *testing.T) {
+		body, _ := gravity.NewBody("Sample", 2, 0, 0, 0)
+		body.SetVelocity(gravity.NewPoint(1, 2, 3))
+
+		tests := []struct {
+			name          string
+			expected, got float64
+		}{
+			{"GetVelocity().GetX", 1, body.GetVelocity().GetX()},
+			{"GetMomentum().GetX", 2, body.GetMomentum().GetX()},
+			{"GetMomentum().GetY", 4, body.GetMomentum().GetY()},
+			{"GetInertia().GetZ", 6, body.GetInertia().GetZ()},
+		}
+
+		for _, test := range tests {
+			if test.got != test.expected {
+				t.Fatalf(
+					"[Body.%v] expected %v, got %v",
+					test.name, test.expected, test.got,
+				)
+			}
+		}
+	})
+
+	t.Run("#SetMomentum", func(t *testing.T) {
+		body, _ := gravity.NewBody("Sample", 2, 0, 0, 0)
+		body.SetMomentum(gravity.NewPoint(2, 4, 6))
+
+		tests := []struct {
+			name          string
+			expected, got float64
+		}{
+			{"GetVelocity().GetX", 1, body.GetVelocity().GetX()},
+			{"GetVelocity().GetY", 2, body.GetVelocity().GetY()},
+			{"GetVelocity().GetZ", 3, body.GetVelocity().GetZ()},
+			{"GetInertia().GetX", 2, body.GetInertia().GetX()},
+		}
+
+		for _, test := range tests {
+			if test.got != test.expected {
+				t.Fatalf(
+					"[Body.%v] expected %v, got %v",
+					test.name, test.expected, test.got,
+				)
+			}
+		}
+	})
+
+	t.Run("NewBodyWithVelocity", func(t *testing.T) {
+		body, err := gravity.NewBodyWithVelocity("Sample", 2, 1, 2, 3, 4, 5, 6)
+
+		if err != nil {
+			t.Fatalf("[NewBodyWithVelocity] unexpected error: %v", err)
+		}
+
+		body.Move(1)
+		tests := []struct {
+			name          string
+			expected, got float64
+		}{
+			{"GetPosition().GetX", 5, body.GetPosition().GetX()},
+			{"GetPosition().GetY", 7, body.GetPosition().GetY()},
+			{"GetPosition().GetZ", 9, body.GetPosition().GetZ()},
+			{"GetMomentum().GetX", 8, body.GetMomentum().GetX()},
+		}
+
+		for _, test := range tests {
+			if test.got != test.expected {
+				t.Fatalf(
+					"[Body.%v] expected %v, got %v",
+					test.name, test.expected, test.got,
+				)
+			}
+		}
+
+		if body, err := gravity.NewBodyWithVelocity("Null", 0, 0, 0, 0, 1, 1, 1); body != nil || err == nil {
+			t.Fatalf("[NewBodyWithVelocity] expected error, got %v", body)
+		}
+	})
+
+	t.Run("NewTestParticle", func(t *testing.T) {
+		body := gravity.NewTestParticle("Dust", 1, 2, 3)
+		body.SetVelocity(gravity.NewPoint(1, 0, 0))
+		body.SetMomentum(gravity.NewPoint(5, 5, 5))
+		body.Move(2)
+
+		tests := []struct {
+			name          string
+			expected, got float64
+		}{
+			{"GetMass", 0, body.GetMass()},
+			{"GetMomentum().Magnitude", 0, body.GetMomentum().Magnitude()},
+			{"GetVelocity().GetX", 1, body.GetVelocity().GetX()},
+			{"GetPosition().GetX", 3, body.GetPosition().GetX()},
+		}
+
+		for _, test := range tests {
+			if test.got != test.expected {
+				t.Fatalf(
+					"[Body.%v] expected %v, got %v",
+					test.name, test.expected, test.got,
+				)
+			}
+		}
+	})
+
+	t.Run("#SetPinned", func(t *testing.T) {
+		body, _ := gravity.NewBodyWithVelocity("Sample", 1, 1, 2, 3, 4, 5, 6)
+
+		if body.IsPinned() {
+			t.Fatal("[Body.IsPinned] expected free body")
+		}
+
+		body.SetPinned(true)
+		if !body.IsPinned() {
+			t.Fatal("[Body.IsPinned] expected pinned body")
+		}
+
+		if got := body.GetVelocity().Magnitude(); got != 0 {
+			t.Fatalf("[Body.SetPinned] expected body at rest, got %v", body.GetVelocity())
+		}
+
+		body.SetVelocity(gravity.NewPoint(1, 1, 1))
+		body.SetVelocityVector(gravity.Vector{X: 1, Y: 1, Z: 1})
+		body.SetMomentum(gravity.NewPoint(1, 1, 1))
+		body.SetInertia(gravity.NewPoint(1, 1, 1))
+		if got := body.GetVelocity().Magnitude(); got != 0 {
+			t.Fatalf("[Body.SetVelocity] expected pinned body at rest, got %v", body.GetVelocity())
+		}
+
+		body.Move(1)
+		if got := body.GetPosition().GetX(); got != 1 {
+			t.Fatalf("[Body.Move] expected pinned body to stay, got %v", body.GetPosition())
+		}
+
+		body.SetPinned(false)
+		body.SetVelocity(gravity.NewPoint(1, 1, 1))
+		if got := body.GetVelocity().GetX(); got != 1 {
+			t.Fatalf("[Body.SetVelocity] expected velocity once unpinned, got %v", body.GetVelocity())
+		}
+	})
+
+	t.Run("#SetCharge", func(t *testing.T) {
+		body, _ := gravity.NewBody("Sample", 1, 0, 0, 0)
+
+		if got := body.GetCharge(); got != 0 {
+			t.Fatalf("[Body.GetCharge] expected neutral body, got %v", got)
+		}
+
+		body.SetCharge(-2)
+		if got := body.GetCharge(); got != -2 {
+			t.Fatalf("[Body.GetCharge] expected -2, got %v", got)
+		}
+	})
 }
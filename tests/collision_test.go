@@ -0,0 +1,164 @@
+package tests
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	gravity "github.com/cacilhas/gravity/system"
+)
+
+func TestCollision(t *testing.T) {
+	t.Run("Overlap", func(t *testing.T) {
+		body1, _ := gravity.NewBody("Sample 1", 1, 0, 0, 0)
+		body2, _ := gravity.NewBody("Sample 2", 1, 3, 4, 0)
+		body3, _ := gravity.NewBody("Sample 3", 1, 0, 0, 0)
+
+		if gravity.Overlap(body1, body2) {
+			t.Fatal("point masses apart should not overlap")
+		}
+
+		if !gravity.Overlap(body1, body3) {
+			t.Fatal("coincident point masses should overlap")
+		}
+
+		body1.SetRadius(2)
+		body2.SetRadius(3)
+		if !gravity.Overlap(body1, body2) {
+			t.Fatal("touching bodies should overlap")
+		}
+	})
+
+	t.Run("MergeBodies", func(t *testing.T) {
+		body1, _ := gravity.NewBody("Light", 1, 0, 0, 0)
+		body1.SetInertia(gravity.NewPoint(2, 0, 0))
+		body1.SetRadius(1)
+		body2, _ := gravity.NewBody("Heavy", 3, 4, 0, 0)
+		body2.SetInertia(gravity.NewPoint(0, 6, 0))
+		body2.SetRadius(2)
+		res, err := gravity.MergeBodies(body1, body2)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got := res.GetName(); got != "Heavy+Light" {
+			t.Fatalf("[MergeBodies] expected Heavy+Light, got %v", got)
+		}
+
+		tests := []struct {
+			name          string
+			expected, got float64
+		}{
+			{"GetMass", 4, res.GetMass()},
+			{"GetPosition().GetX", 3, res.GetPosition().GetX()},
+			{"GetInertia().GetX", 2, res.GetInertia().GetX()},
+			{"GetInertia().GetY", 6, res.GetInertia().GetY()},
+			{"GetRadius", math.Cbrt(9), res.GetRadius()},
+		}
+
+		for _, test := range tests {
+			if math.Abs(test.got-test.expected) > 1e-12 {
+				t.Fatalf(
+					"[MergeBodies %v] expected %v, got %v",
+					test.name, test.expected, test.got,
+				)
+			}
+		}
+	})
+
+	t.Run("merge on #Step", func(t *testing.T) {
+		body1, _ := gravity.NewBody("Sun", 100, 0, 0, 0)
+		body1.SetRadius(1)
+		body2, _ := gravity.NewBody("Comet", 1, 5, 0, 0)
+		body2.SetRadius(1)
+		body2.SetInertia(gravity.NewPoint(-4, 0, 0))
+		body3, _ := gravity.NewBody("Planet", 10, 0, 50, 0)
+		system, _ := gravity.NewSystem(body1, body2, body3)
+
+		if err := system.Step(1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got := len(system.GetMerges()); got != 1 {
+			t.Fatalf("expected 1 merge, got %v", got)
+		}
+
+		merge := system.GetMerges()[0]
+		if merge.Result != system.GetBody("Sun+Comet") {
+			t.Fatalf("expected Sun+Comet in system, got %v", merge.Result)
+		}
+
+		if system.GetBody("Sun") != nil || system.GetBody("Comet") != nil {
+			t.Fatal("expected merged bodies to be removed")
+		}
+
+		if got := system.TotalMass(); got != 111 {
+			t.Fatalf("expected total mass of 111Kg, got %vKg", got)
+		}
+
+		if got := merge.Time; got != 1 {
+			t.Fatalf("expected merge at time 1, got %v", got)
+		}
+	})
+
+	t.Run("merged name clash", func(t *testing.T) {
+		body1, _ := gravity.NewBody("A", 2, 0, 0, 0)
+		body2, _ := gravity.NewBody("B", 1, 0, 0, 0)
+		body3, _ := gravity.NewBody("A+B", 5, 1e+6, 0, 0)
+		body4, _ := gravity.NewBody("A+B#2", 5, -1e+6, 0, 0)
+		system, _ := gravity.NewSystem(body1, body2, body3, body4)
+
+		if err := system.Step(1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := system.Step(1); err != nil {
+			t.Fatalf("unexpected error on next step: %v", err)
+		}
+
+		if got := len(system.GetBodies()); got != 3 {
+			t.Fatalf("expected 3 bodies, got %v", got)
+		}
+		if system.GetBody("A+B") != body3 || system.GetBody("A+B#2") != body4 {
+			t.Fatal("expected bodies named after a merge to stay")
+		}
+		if merged := system.GetBody("A+B#3"); merged == nil || merged != system.GetMerges()[0].Result {
+			t.Fatalf("expected A+B#3 in system, got %v", system.GetBodies())
+		}
+		if got := system.TotalMass(); got != 13 {
+			t.Fatalf("expected total mass of 13Kg, got %vKg", got)
+		}
+	})
+
+	// test particles neither collide nor meet each other, so a Step costs
+	// O(M·N) for M massive bodies out of N: four times the particles should
+	// take about four times as long, not sixteen
+	t.Run("scaling with test particles", func(t *testing.T) {
+		step := func(n int) time.Duration {
+			sun, _ := gravity.NewBody("Sun", 2e+30, 0, 0, 0)
+			system, _ := gravity.NewSystem(sun)
+			system.SetEncounterDistance(1)
+			system.Subscribe(gravity.CloseEncounter, func(gravity.System, gravity.Event) error { return nil })
+			system.Subscribe(gravity.Periapsis, func(gravity.System, gravity.Event) error { return nil })
+			for i := 0; i < n; i++ {
+				system.AddBody(gravity.NewTestParticle(fmt.Sprintf("Dust %v", i), 1e+11+float64(i)*1e+6, 0, 0))
+			}
+
+			best := time.Duration(math.MaxInt64)
+			for i := 0; i < 5; i++ {
+				start := time.Now()
+				system.Step(1)
+				if elapsed := time.Since(start); elapsed < best {
+					best = elapsed
+				}
+			}
+			return best
+		}
+
+		small, large := step(1000), step(4000)
+		if ratio := float64(large) / float64(small); ratio > 8 {
+			t.Fatalf("expected about linear cost, got ×%.1f for ×4 particles (%v, %v)", ratio, small, large)
+		}
+	})
+}
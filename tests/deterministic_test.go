@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	gravity "github.com/cacilhas/gravity/system"
+)
+
+func TestDeterministic(t *testing.T) {
+	// run builds the same cluster, adding bodies in the given order, and
+	// returns the final states by name; velocities start small, so they
+	// show differences in the last bits of accelerations
+	run := func(order []int, solver gravity.Solver, force gravity.PairForce, deterministic bool) map[string][2]gravity.Vector {
+		random := rand.New(rand.NewSource(11))
+		bodies := make([]gravity.Body, len(order))
+		for i := range bodies {
+			bodies[i], _ = gravity.NewBodyWithVelocity(
+				fmt.Sprintf("Body %v", i),
+				1e+20+random.Float64()*1e+22,
+				random.NormFloat64()*1e+9, random.NormFloat64()*1e+9, random.NormFloat64()*1e+9,
+				random.NormFloat64()*1e+3, random.NormFloat64()*1e+3, random.NormFloat64()*1e+3,
+			)
+		}
+		bodies[0] = gravity.NewTestParticle("Dust", 1e+9, 0, 0)
+
+		system, _ := gravity.NewSystemWithIntegrator(gravity.NewLeapfrog())
+		system.SetSoftening(1e+6)
+		system.SetSolver(solver)
+		system.SetPairForce(force)
+		system.SetDeterministic(deterministic)
+		for _, i := range order {
+			system.AddBody(bodies[i])
+		}
+		system.Recenter()
+
+		for i := 0; i < 200; i++ {
+			system.Step(1000)
+		}
+
+		res := make(map[string][2]gravity.Vector)
+		for name, b := range system.GetBodies() {
+			res[name] = [2]gravity.Vector{b.GetPositionVector(), b.GetVelocityVector()}
+		}
+		return res
+	}
+
+	compare := func(t *testing.T, label string, expected, got map[string][2]gravity.Vector) {
+		for name, state := range expected {
+			if got[name] != state {
+				t.Fatalf("[%v %v] expected %v, got %v", label, name, state, got[name])
+			}
+		}
+	}
+
+	forward := make([]int, 60)
+	backward := make([]int, len(forward))
+	for i := range forward {
+		forward[i] = i
+		backward[len(backward)-1-i] = i
+	}
+	sum, _ := gravity.NewSumForce(gravity.NewNewtonian())
+	forces := []gravity.PairForce{gravity.NewNewtonian(), sum}
+
+	t.Run("#SetDeterministic", func(t *testing.T) {
+		system, _ := gravity.NewSystem()
+
+		if system.IsDeterministic() {
+			t.Fatal("expected non-deterministic reductions by default")
+		}
+
+		system.SetDeterministic(true)
+		if !system.IsDeterministic() {
+			t.Fatal("expected deterministic reductions")
+		}
+	})
+
+	t.Run("insertion order", func(t *testing.T) {
+		serial, _ := gravity.NewPairwiseSolverWithWorkers(3)
+		barnesHut, _ := gravity.NewBarnesHutSolver(0.5)
+
+		for _, force := range forces {
+			for _, solver := range []gravity.Solver{serial, barnesHut} {
+				label := fmt.Sprintf("%T %T", solver, force)
+				compare(t, label, run(forward, solver, force, false), run(backward, solver, force, false))
+			}
+		}
+	})
+
+	t.Run("worker count", func(t *testing.T) {
+		for _, force := range forces {
+			serial, _ := gravity.NewPairwiseSolverWithWorkers(1)
+			expected := run(forward, serial, force, true)
+
+			for _, workers := range []int{2, 3, 7, 32} {
+				solver, _ := gravity.NewPairwiseSolverWithWorkers(workers)
+				label := fmt.Sprintf("%T %v workers", force, workers)
+				compare(t, label, expected, run(backward, solver, force, true))
+			}
+		}
+	})
+}
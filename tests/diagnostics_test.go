@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	gravity "github.com/cacilhas/gravity/system"
+)
+
+func TestDiagnostics(t *testing.T) {
+	binary := func() gravity.System {
+		body1, _ := gravity.NewBody("Sun", 3e+10, 0, 0, 0)
+		body1.SetInertia(gravity.NewPoint(0, -3, 0))
+		body2, _ := gravity.NewBody("Planet", 1e+10, 4, 0, 0)
+		body2.SetInertia(gravity.NewPoint(0, 3, 0))
+		system, _ := gravity.NewSystem(body1, body2)
+		return system
+	}
+
+	t.Run("quantities", func(t *testing.T) {
+		system := binary()
+		potential := -gravity.G * 3e+10 * 1e+10 / 4
+		kinetic := 9/(2*3e+10) + 9/(2*1e+10)
+
+		tests := []struct {
+			name          string
+			expected, got float64
+		}{
+			{"KineticEnergy", kinetic, system.KineticEnergy()},
+			{"PotentialEnergy", potential, system.PotentialEnergy()},
+			{"Momentum", 0, system.Momentum().Magnitude()},
+			{"AngularMomentum Z", 12, system.AngularMomentum().GetZ()},
+			{"CenterOfMass X", 1, system.CenterOfMass().GetX()},
+			{"VirialRatio", 2 * kinetic / -potential, system.VirialRatio()},
+			{"Diagnose().Energy", kinetic + potential, system.Diagnose().Energy()},
+		}
+
+		for _, test := range tests {
+			if math.Abs(test.got-test.expected) > 1e-12*math.Abs(test.expected)+1e-15 {
+				t.Fatalf(
+					"[System.%v] expected %v, got %v",
+					test.name, test.expected, test.got,
+				)
+			}
+		}
+	})
+
+	t.Run("softened potential", func(t *testing.T) {
+		system := binary()
+		system.SetSoftening(3)
+		expected := -gravity.G * 3e+10 * 1e+10 / 5
+
+		if got := system.PotentialEnergy(); math.Abs(got-expected) > 1e-12*math.Abs(expected) {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("empty system", func(t *testing.T) {
+		system, _ := gravity.NewSystem()
+		d := system.Diagnose()
+
+		if d.Energy() != 0 || d.VirialRatio != 0 || d.CenterOfMass.Magnitude() != 0 {
+			t.Fatalf("expected null diagnostics, got %+v", d)
+		}
+	})
+
+	t.Run("#GetDrift", func(t *testing.T) {
+		system := binary()
+		system.SetIntegrator(gravity.NewRK4())
+		baseline := system.GetBaseline()
+
+		for i := 0; i < 100; i++ {
+			system.Step(0.01)
+		}
+
+		drift := system.GetDrift()
+		if drift.Energy > 1e-9 || drift.Momentum > 1e-12 || drift.AngularMomentum > 1e-9 {
+			t.Fatalf("expected conserved quantities, got %+v", drift)
+		}
+
+		if got := system.GetBaseline(); got.Time != baseline.Time || got.Energy() != baseline.Energy() {
+			t.Fatalf("expected baseline %+v, got %+v", baseline, got)
+		}
+
+		system.GetBody("Planet").SetInertia(gravity.NewPoint(0, 6, 0))
+		if got := system.GetDrift(); got.Momentum == 0 {
+			t.Fatal("expected momentum drift to be noticed")
+		}
+
+		system.ResetBaseline()
+		if got := system.GetDrift(); got != (gravity.Drift{}) {
+			t.Fatalf("expected no drift after reset, got %+v", got)
+		}
+	})
+
+	t.Run("MomentumConserved", func(t *testing.T) {
+		system := binary()
+
+		if !system.Diagnose().MomentumConserved {
+			t.Fatal("expected free system to conserve momentum")
+		}
+
+		system.GetBody("Sun").SetPinned(true)
+		if system.Diagnose().MomentumConserved {
+			t.Fatal("expected pinned system not to conserve momentum")
+		}
+
+		// a pinned body stays at rest, whatever velocity it is given
+		pinned := system.Diagnose()
+		system.GetBody("Sun").SetVelocity(gravity.NewPoint(1e+3, 0, 0))
+		if got := system.Diagnose(); got.Kinetic != pinned.Kinetic || got.Momentum != pinned.Momentum {
+			t.Fatalf("expected pinned body not to count, got %+v", got)
+		}
+
+		system.GetBody("Sun").SetPinned(false)
+		mond, _ := gravity.NewMOND(1e-10)
+		system.SetPairForce(mond)
+		if system.Diagnose().MomentumConserved {
+			t.Fatal("expected MOND system not to conserve momentum")
+		}
+
+		system.SetPairForce(gravity.NewNewtonian())
+		system.AddForce(gravity.NewUniformField(gravity.NewPoint(1, 0, 0)))
+		if system.Diagnose().MomentumConserved {
+			t.Fatal("expected forced system not to conserve momentum")
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	gravity "github.com/cacilhas/gravity/system"
+)
+
+func TestEscape(t *testing.T) {
+	// GM ≈ 10, so escape velocity is √2 at distance 10
+	mass := 10 / gravity.G
+	setup := func(speed float64) (gravity.System, gravity.Body) {
+		sun, _ := gravity.NewBody("Sun", mass, 0, 0, 0)
+		probe, _ := gravity.NewBodyWithVelocity("Probe", 1e-20, 10, 0, 0, speed, 0, 0)
+		system, _ := gravity.NewSystem(sun, probe)
+		return system, probe
+	}
+
+	t.Run("#SetEscapePolicy", func(t *testing.T) {
+		system, _ := gravity.NewSystem()
+
+		if got := system.GetEscapePolicy(); got.Radius != 0 || got.Remove {
+			t.Fatalf("expected detection off by default, got %v", got)
+		}
+
+		if err := system.SetEscapePolicy(gravity.EscapePolicy{Radius: -1}); err == nil {
+			t.Fatal("error not raised")
+		}
+
+		policy := gravity.EscapePolicy{Radius: 5, Remove: true}
+		system.SetEscapePolicy(policy)
+		if got := system.GetEscapePolicy(); got != policy {
+			t.Fatalf("expected %v, got %v", policy, got)
+		}
+	})
+
+	t.Run("bound body", func(t *testing.T) {
+		system, _ := setup(1)
+		system.SetEscapePolicy(gravity.EscapePolicy{Radius: 5, Remove: true})
+		system.Step(0.01)
+
+		if got := system.GetEscapes(); len(got) != 0 {
+			t.Fatalf("expected no escape, got %v", got)
+		}
+	})
+
+	t.Run("inside radius", func(t *testing.T) {
+		system, _ := setup(2)
+		system.SetEscapePolicy(gravity.EscapePolicy{Radius: 20, Remove: true})
+		system.Step(0.01)
+
+		if got := system.GetEscapes(); len(got) != 0 {
+			t.Fatalf("expected no escape, got %v", got)
+		}
+	})
+
+	t.Run("removal", func(t *testing.T) {
+		system, probe := setup(2)
+		system.SetEscapePolicy(gravity.EscapePolicy{Radius: 5, Remove: true})
+		system.Step(0.01)
+		escapes := system.GetEscapes()
+
+		if len(escapes) != 1 {
+			t.Fatalf("expected a single escape, got %v", escapes)
+		}
+
+		escape := escapes[0]
+		tests := []struct {
+			name          string
+			expected, got float64
+		}{
+			{"Time", 0.01, escape.Time},
+			{"Distance", 10.02, escape.Distance},
+			{"Speed", 2, escape.Speed},
+			{"EscapeVelocity", math.Sqrt(2 / 1.002), escape.EscapeVelocity},
+		}
+
+		for _, test := range tests {
+			if math.Abs(test.got-test.expected) > 1e-3 {
+				t.Fatalf(
+					"[Escape.%v] expected %v, got %v",
+					test.name, test.expected, test.got,
+				)
+			}
+		}
+
+		if escape.Body != probe || !escape.Removed {
+			t.Fatalf("expected probe removed, got %v", escape)
+		}
+
+		if got := system.GetBody("Probe"); got != nil {
+			t.Fatalf("expected probe gone, got %v", got)
+		}
+	})
+
+	t.Run("kept", func(t *testing.T) {
+		system, probe := setup(2)
+		system.SetEscapePolicy(gravity.EscapePolicy{Radius: 5})
+		system.Step(0.01)
+		system.Step(0.01)
+
+		if got := len(system.GetEscapes()); got != 1 {
+			t.Fatalf("expected a single report, got %v", got)
+		}
+
+		if got := system.GetBody("Probe"); got != probe {
+			t.Fatalf("expected probe kept, got %v", got)
+		}
+	})
+}
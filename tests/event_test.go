@@ -0,0 +1,206 @@
+package tests
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	gravity "github.com/cacilhas/gravity/system"
+)
+
+func TestEvent(t *testing.T) {
+	// GM ≈ 10
+	mass := 10 / gravity.G
+
+	record := func(system gravity.System, kinds ...gravity.EventKind) *[]gravity.Event {
+		events := []gravity.Event{}
+		for _, kind := range kinds {
+			system.Subscribe(kind, func(_ gravity.System, e gravity.Event) error {
+				events = append(events, e)
+				return nil
+			})
+		}
+		return &events
+	}
+
+	t.Run("#Subscribe", func(t *testing.T) {
+		system, _ := gravity.NewSystem()
+
+		if _, err := system.Subscribe(gravity.Collision, nil); err == nil {
+			t.Fatal("[Subscribe] error not raised for nil handler")
+		}
+
+		if _, err := system.Subscribe(gravity.EventKind(42), func(gravity.System, gravity.Event) error { return nil }); err == nil {
+			t.Fatal("[Subscribe] error not raised for unknown kind")
+		}
+
+		id, err := system.Subscribe(gravity.Collision, func(gravity.System, gravity.Event) error { return nil })
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !system.Unsubscribe(id) {
+			t.Fatal("[Unsubscribe] expected subscription removed")
+		}
+
+		if system.Unsubscribe(id) {
+			t.Fatal("[Unsubscribe] expected subscription gone")
+		}
+	})
+
+	t.Run("#SetEncounterDistance", func(t *testing.T) {
+		system, _ := gravity.NewSystem()
+
+		if err := system.SetEncounterDistance(-1); err == nil {
+			t.Fatal("error not raised")
+		}
+
+		system.SetEncounterDistance(2)
+		if got := system.GetEncounterDistance(); got != 2 {
+			t.Fatalf("expected 2, got %v", got)
+		}
+	})
+
+	t.Run("collision", func(t *testing.T) {
+		body1, _ := gravity.NewBody("A", 1, 0, 0, 0)
+		body2, _ := gravity.NewBodyWithVelocity("B", 1, 3, 0, 0, -2, 0, 0)
+		body1.SetRadius(1)
+		body2.SetRadius(1)
+		system, _ := gravity.NewSystem(body1, body2)
+		events := record(system, gravity.Collision)
+		system.Step(1)
+
+		if len(*events) != 1 {
+			t.Fatalf("expected a single collision, got %v", *events)
+		}
+
+		e := (*events)[0]
+		if e.Kind != gravity.Collision || e.Time != 1 || e.Result != system.GetBody("A+B") || math.Abs(e.Speed-2) > 1e-6 {
+			t.Fatalf("unexpected collision: %v", e)
+		}
+	})
+
+	t.Run("close encounter", func(t *testing.T) {
+		body1, _ := gravity.NewBody("A", 1, 0, 0, 0)
+		body2, _ := gravity.NewBodyWithVelocity("B", 1, 3, 0, 0, -1, 0, 0)
+		system, _ := gravity.NewSystem(body1, body2)
+		system.SetEncounterDistance(2.2)
+		events := record(system, gravity.CloseEncounter)
+
+		for i := 0; i < 3; i++ {
+			system.Step(0.5)
+		}
+
+		if len(*events) != 1 {
+			t.Fatalf("expected a single encounter, got %v", *events)
+		}
+
+		if e := (*events)[0]; e.Time != 1 || math.Abs(e.Distance-2) > 1e-6 || e.Bodies != [2]gravity.Body{body1, body2} {
+			t.Fatalf("unexpected encounter: %v", e)
+		}
+	})
+
+	t.Run("escape", func(t *testing.T) {
+		sun, _ := gravity.NewBody("Sun", mass, 0, 0, 0)
+		probe, _ := gravity.NewBodyWithVelocity("Probe", 1e-20, 10, 0, 0, 2, 0, 0)
+		system, _ := gravity.NewSystem(sun, probe)
+		system.SetEscapePolicy(gravity.EscapePolicy{Radius: 5, Remove: true})
+		events := record(system, gravity.EscapeEvent)
+		system.Step(0.01)
+
+		if len(*events) != 1 || (*events)[0].Bodies[0] != probe || (*events)[0].Bodies[1] != nil {
+			t.Fatalf("expected probe escape, got %v", *events)
+		}
+	})
+
+	t.Run("periapsis", func(t *testing.T) {
+		sun, _ := gravity.NewBody("Sun", mass, 0, 0, 0)
+		planet, _ := gravity.NewBody("Planet", 1e-20, 10, 0, 0)
+		system, _ := gravity.NewSystemWithIntegrator(gravity.NewRK4(), sun, planet)
+		system.SetElements(planet, sun, gravity.Elements{
+			SemiMajorAxis: 10,
+			Eccentricity:  0.5,
+			MeanAnomaly:   -0.1,
+		})
+		period, _ := system.OrbitalPeriod(planet, sun)
+		events := record(system, gravity.Periapsis)
+
+		for i := 0; i < 1000; i++ {
+			system.Step(period / 1000)
+		}
+
+		if len(*events) != 1 {
+			t.Fatalf("expected a single periapsis, got %v", *events)
+		}
+
+		e := (*events)[0]
+		if e.Bodies != [2]gravity.Body{planet, sun} || math.Abs(e.Distance-5) > 1e-3 {
+			t.Fatalf("unexpected periapsis: %v", e)
+		}
+
+		if expected := 0.1 / (2 * math.Pi) * period; math.Abs(e.Time-expected) > period/1000 {
+			t.Fatalf("expected periapsis at %v, got %v", expected, e.Time)
+		}
+	})
+
+	t.Run("order and stop", func(t *testing.T) {
+		system, _ := gravity.NewSystem()
+		for _, name := range []string{"D", "C", "B", "A"} {
+			b, _ := gravity.NewBody(name, 1, 0, 0, 0)
+			b.SetRadius(1)
+			system.AddBody(b)
+		}
+		body1, _ := gravity.NewBody("Z", 1, 100, 0, 0)
+		body2, _ := gravity.NewBody("Y", 1, 100, 3, 0)
+		system.AddBody(body1)
+		system.AddBody(body2)
+		system.SetEncounterDistance(5)
+
+		got := []string{}
+		stop := fmt.Errorf("stop")
+		handler := func(_ gravity.System, e gravity.Event) error {
+			got = append(got, fmt.Sprintf("%v %v", e.Kind, e.Bodies[0].GetName()))
+			if e.Kind == gravity.CloseEncounter {
+				return stop
+			}
+			return nil
+		}
+		system.Subscribe(gravity.Collision, handler)
+		system.Subscribe(gravity.CloseEncounter, handler)
+
+		if err := system.Step(0); err != stop {
+			t.Fatalf("expected stop, got %v", err)
+		}
+
+		expected := []string{"collision A", "collision A+B", "collision A+B+C", "close encounter Y"}
+		if fmt.Sprint(got) != fmt.Sprint(expected) {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+
+		if err := system.Status(); err != nil {
+			t.Fatalf("expected system still usable, got %v", err)
+		}
+	})
+
+	t.Run("changing bodies", func(t *testing.T) {
+		body1, _ := gravity.NewBody("A", 1, 0, 0, 0)
+		body2, _ := gravity.NewBody("B", 1, 0, 0, 0)
+		system, _ := gravity.NewSystem(body1, body2)
+		system.Subscribe(gravity.Collision, func(s gravity.System, e gravity.Event) error {
+			s.RemoveBody(e.Result)
+			return nil
+		})
+
+		if err := system.Step(1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got := len(system.GetBodies()); got != 0 {
+			t.Fatalf("expected no body left, got %v", got)
+		}
+
+		if err := system.Step(1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,134 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	gravity "github.com/cacilhas/gravity/system"
+)
+
+func TestForce(t *testing.T) {
+	t.Run("invalid forces", func(t *testing.T) {
+		if f, err := gravity.NewLinearDrag(-1); f != nil || err == nil {
+			t.Fatalf("[NewLinearDrag] expected error, got %v", f)
+		}
+
+		if f, err := gravity.NewQuadraticDrag(math.NaN()); f != nil || err == nil {
+			t.Fatalf("[NewQuadraticDrag] expected error, got %v", f)
+		}
+
+		if f, err := gravity.NewThrust("Rocket", nil); f != nil || err == nil {
+			t.Fatalf("[NewThrust] expected error, got %v", f)
+		}
+
+		system, _ := gravity.NewSystem()
+		if err := system.AddForce(nil); err == nil {
+			t.Fatal("[AddForce] error not raised")
+		}
+	})
+
+	t.Run("#AddForce", func(t *testing.T) {
+		system, _ := gravity.NewSystem()
+		field := gravity.NewUniformField(gravity.NewPoint(0, 0, -9.8))
+		drag, _ := gravity.NewLinearDrag(1)
+		system.AddForce(field)
+		system.AddForce(drag)
+
+		if got := len(system.GetForces()); got != 2 {
+			t.Fatalf("expected 2 forces, got %v", got)
+		}
+
+		if !system.RemoveForce(field) {
+			t.Fatalf("expected notified remotion of %v", field)
+		}
+
+		if got := system.GetForces(); len(got) != 1 || got[0] != drag {
+			t.Fatalf("expected only drag left, got %v", got)
+		}
+
+		if system.RemoveForce(field) {
+			t.Fatalf("%v already removed, but renotified", field)
+		}
+	})
+
+	t.Run("uniform field", func(t *testing.T) {
+		ball, _ := gravity.NewBodyWithVelocity("Ball", 1e-10, 0, 0, 0, 3, 0, 0)
+		system, _ := gravity.NewSystemWithIntegrator(gravity.NewVelocityVerlet(), ball)
+		system.AddForce(gravity.NewUniformField(gravity.NewPoint(0, 0, -10)))
+
+		for i := 0; i < 10; i++ {
+			system.Step(0.1)
+		}
+
+		tests := []struct {
+			name          string
+			expected, got float64
+		}{
+			{"x", 3, ball.GetPosition().GetX()},
+			{"z", -5, ball.GetPosition().GetZ()},
+			{"velocity z", -10, ball.GetVelocity().GetZ()},
+		}
+
+		for _, test := range tests {
+			if math.Abs(test.got-test.expected) > 1e-9 {
+				t.Fatalf(
+					"[UniformField %v] expected %v, got %v",
+					test.name, test.expected, test.got,
+				)
+			}
+		}
+	})
+
+	t.Run("drag", func(t *testing.T) {
+		linear, _ := gravity.NewLinearDrag(2)
+		quadratic, _ := gravity.NewQuadraticDrag(2)
+
+		tests := []struct {
+			name     string
+			force    gravity.ExternalForce
+			expected float64
+		}{
+			{"linear", linear, 4 * math.Exp(-1)},
+			{"quadratic", quadratic, 4.0 / (1 + 4)},
+		}
+
+		for _, test := range tests {
+			ball, _ := gravity.NewBodyWithVelocity("Ball", 2, 0, 0, 0, 4, 0, 0)
+			system, _ := gravity.NewSystemWithIntegrator(gravity.NewRK4(), ball)
+			system.AddForce(test.force)
+
+			for i := 0; i < 100; i++ {
+				system.Step(0.01)
+			}
+
+			if got := ball.GetVelocity().GetX(); math.Abs(got-test.expected) > 1e-6 {
+				t.Fatalf(
+					"[%v drag] expected %v, got %v",
+					test.name, test.expected, got,
+				)
+			}
+		}
+	})
+
+	t.Run("thrust", func(t *testing.T) {
+		rocket, _ := gravity.NewBody("Rocket", 1e-10, 0, 0, 0)
+		debris, _ := gravity.NewBody("Debris", 1e-10, 10, 0, 0)
+		system, _ := gravity.NewSystemWithIntegrator(gravity.NewRK4(), rocket, debris)
+		thrust, _ := gravity.NewThrust("Rocket", func(b gravity.Body, t float64) gravity.Point {
+			return gravity.NewPoint(b.GetMass()*6*t, 0, 0)
+		})
+		system.AddForce(thrust)
+
+		for i := 0; i < 10; i++ {
+			system.Step(0.1)
+		}
+
+		if got := rocket.GetPosition().GetX(); math.Abs(got-1) > 1e-9 {
+			t.Fatalf("expected rocket at 1 (x = t³), got %v", got)
+		}
+
+		if got := debris.GetPosition().GetX(); math.Abs(got-10) > 1e-9 {
+			t.Fatalf("expected debris to stay, got %v", got)
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	gravity "github.com/cacilhas/gravity/system"
+)
+
+func TestFrame(t *testing.T) {
+	t.Run("#Recenter", func(t *testing.T) {
+		body1, _ := gravity.NewBody("Sun", 3, 1, 1, 0)
+		body1.SetInertia(gravity.NewPoint(3, 0, 0))
+		body2, _ := gravity.NewBody("Planet", 1, 5, 1, 0)
+		body2.SetInertia(gravity.NewPoint(1, 4, 0))
+		system, _ := gravity.NewSystem(body1, body2)
+		system.Recenter()
+
+		tests := []struct {
+			name          string
+			expected, got float64
+		}{
+			{"CenterOfMass", 0, system.CenterOfMass().Magnitude()},
+			{"Momentum", 0, system.Momentum().Magnitude()},
+			{"Sun X", -1, body1.GetPosition().GetX()},
+			{"Planet X", 3, body2.GetPosition().GetX()},
+			{"Sun inertia X", 0, body1.GetInertia().GetX()},
+			{"Sun inertia Y", -3, body1.GetInertia().GetY()},
+			{"Planet inertia Y", 3, body2.GetInertia().GetY()},
+		}
+
+		for _, test := range tests {
+			if math.Abs(test.got-test.expected) > 1e-12 {
+				t.Fatalf(
+					"[System.Recenter %v] expected %v, got %v",
+					test.name, test.expected, test.got,
+				)
+			}
+		}
+	})
+
+	t.Run("#SetAutoRecenter", func(t *testing.T) {
+		body1, _ := gravity.NewBody("Sun", 3, 1, 1, 0)
+		system, _ := gravity.NewSystem(body1)
+
+		if system.IsAutoRecenter() {
+			t.Fatal("expected system to start without auto recentering")
+		}
+
+		system.SetAutoRecenter(true)
+		if got := body1.GetPosition().Magnitude(); got != 0 {
+			t.Fatalf("expected Sun at the origin, got %v", body1.GetPosition())
+		}
+
+		body2, _ := gravity.NewBody("Planet", 1, 4, 0, 0)
+		body2.SetInertia(gravity.NewPoint(0, 2, 0))
+		system.AddBody(body2)
+
+		if got := system.CenterOfMass().Magnitude(); got > 1e-12 {
+			t.Fatalf("expected centred system, got %v", system.CenterOfMass())
+		}
+
+		if got := system.Momentum().Magnitude(); got > 1e-12 {
+			t.Fatalf("expected still system, got momentum %v", system.Momentum())
+		}
+	})
+
+	t.Run("pinned bodies", func(t *testing.T) {
+		sun, _ := gravity.NewBody("Sun", 3, 5, 0, 0)
+		sun.SetPinned(true)
+		system, _ := gravity.NewSystem(sun)
+		system.SetAutoRecenter(true)
+
+		planet, _ := gravity.NewBodyWithVelocity("Planet", 1, 9, 0, 0, 0, 2, 0)
+		system.AddBody(planet)
+		system.Recenter()
+
+		tests := []struct {
+			name          string
+			expected, got gravity.Point
+		}{
+			{"Sun position", gravity.NewPoint(5, 0, 0), sun.GetPosition()},
+			{"Sun velocity", gravity.NewPoint(0, 0, 0), sun.GetVelocity()},
+			{"Planet position", gravity.NewPoint(9, 0, 0), planet.GetPosition()},
+			{"Planet velocity", gravity.NewPoint(0, 2, 0), planet.GetVelocity()},
+		}
+		for _, test := range tests {
+			if test.got.Diff(test.expected).Magnitude() != 0 {
+				t.Fatalf("[System.Recenter %v] expected %v, got %v", test.name, test.expected, test.got)
+			}
+		}
+	})
+}
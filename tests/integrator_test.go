@@ -0,0 +1,234 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	gravity "github.com/cacilhas/gravity/system"
+)
+
+func TestIntegrator(t *testing.T) {
+	// radial infall keeps Grav exact, since bodies share the x axis
+	energy := func(system gravity.System) float64 {
+		var kinetic, potential float64
+		bodies := []gravity.Body{}
+		for _, b := range system.GetBodies() {
+			p := b.GetInertia().Magnitude()
+			kinetic += p * p / (2 * b.GetMass())
+			bodies = append(bodies, b)
+		}
+		for i, b1 := range bodies {
+			for _, b2 := range bodies[i+1:] {
+				d := b1.GetPosition().Diff(b2.GetPosition()).Magnitude()
+				potential -= gravity.G * b1.GetMass() * b2.GetMass() / d
+			}
+		}
+		return kinetic + potential
+	}
+
+	drift := func(integrator gravity.Integrator) float64 {
+		body1, _ := gravity.NewBody("Sun", 1.5e+11, 0, 0, 0)
+		body2, _ := gravity.NewBody("Probe", 1, 10, 0, 0)
+		system, _ := gravity.NewSystemWithIntegrator(integrator, body1, body2)
+		e0 := energy(system)
+		for i := 0; i < 500; i++ {
+			if err := system.Step(0.01); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		return math.Abs((energy(system) - e0) / e0)
+	}
+
+	t.Run("nil integrator", func(t *testing.T) {
+		system, err := gravity.NewSystemWithIntegrator(nil)
+
+		if system != nil {
+			t.Fatalf("expected no system, got %v", system)
+		}
+
+		if err == nil {
+			t.Fatal("error not raised")
+		}
+	})
+
+	t.Run("#GetIntegrator", func(t *testing.T) {
+		system, _ := gravity.NewSystem()
+
+		if got := system.GetIntegrator(); got != gravity.NewEuler() {
+			t.Fatalf("expected Euler as default, got %v", got)
+		}
+
+		if err := system.SetIntegrator(gravity.NewRK4()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got := system.GetIntegrator(); got != gravity.NewRK4() {
+			t.Fatalf("expected RK4, got %v", got)
+		}
+
+		if err := system.SetIntegrator(nil); err == nil {
+			t.Fatal("error not raised")
+		}
+	})
+
+	// Step used to add the raw force to the inertia whatever dt; the default
+	// Euler integrator kicks by a·dt, then drifts by v·dt
+	t.Run("default step", func(t *testing.T) {
+		body1, _ := gravity.NewBody("Sun", 1.5e+11, 0, 0, 0)
+		body2, _ := gravity.NewBody("Probe", 2, 10, 0, 0)
+		body2.SetVelocity(gravity.NewPoint(0, 3, 0))
+		system, _ := gravity.NewSystem(body1, body2)
+		dt := 0.5
+		system.Step(dt)
+
+		a := -gravity.G * 1.5e+11 / 100
+		tests := []struct {
+			name          string
+			expected, got float64
+		}{
+			{"vx", a * dt, body2.GetVelocity().GetX()},
+			{"vy", 3, body2.GetVelocity().GetY()},
+			{"x", 10 + a*dt*dt, body2.GetPosition().GetX()},
+			{"y", 3 * dt, body2.GetPosition().GetY()},
+		}
+		for _, test := range tests {
+			if math.Abs(test.got-test.expected) > 1e-12*math.Abs(test.expected) {
+				t.Fatalf("[System.Step %v] expected %v, got %v", test.name, test.expected, test.got)
+			}
+		}
+	})
+
+	t.Run("legacy step", func(t *testing.T) {
+		body1, _ := gravity.NewBody("Sun", 1.5e+11, 0, 0, 0)
+		body2, _ := gravity.NewBody("Probe", 2, 3, 4, 0)
+		body2.SetVelocity(gravity.NewPoint(0, 3, 0))
+		system, _ := gravity.NewSystemWithIntegrator(gravity.NewLegacyEuler(), body1, body2)
+		system.SetLegacyGravity(true)
+		dt := 0.5
+		system.Step(dt)
+
+		// the raw force on every axis, over the mass, whatever dt
+		a := gravity.G * 1.5e+11 / 25
+		tests := []struct {
+			name          string
+			expected, got float64
+		}{
+			{"vx", -a, body2.GetVelocity().GetX()},
+			{"vy", 3 - a, body2.GetVelocity().GetY()},
+			{"x", 3 - a*dt, body2.GetPosition().GetX()},
+			{"y", 4 + (3-a)*dt, body2.GetPosition().GetY()},
+		}
+		for _, test := range tests {
+			if math.Abs(test.got-test.expected) > 1e-12*math.Abs(test.expected) {
+				t.Fatalf("[System.Step %v] expected %v, got %v", test.name, test.expected, test.got)
+			}
+		}
+	})
+
+	t.Run("#GetTime", func(t *testing.T) {
+		body, _ := gravity.NewBody("Sample", 1, 0, 0, 0)
+		system, _ := gravity.NewSystem(body)
+		system.Step(0.5)
+		system.Step(0.25)
+
+		if got := system.GetTime(); got != 0.75 {
+			t.Fatalf("expected 0.75, got %v", got)
+		}
+
+		if err := system.Step(-1); err == nil {
+			t.Fatal("error not raised")
+		}
+	})
+
+	t.Run("free motion", func(t *testing.T) {
+		integrators := []gravity.Integrator{
+			gravity.NewEuler(),
+			gravity.NewLeapfrog(),
+			gravity.NewVelocityVerlet(),
+			gravity.NewRK4(),
+		}
+
+		for _, integrator := range integrators {
+			body, _ := gravity.NewBody("Sample", 2, 1, 2, 3)
+			body.SetInertia(gravity.NewPoint(2, 4, 6))
+			system, _ := gravity.NewSystemWithIntegrator(integrator, body)
+			system.Step(2)
+			pos := body.GetPosition()
+
+			tests := []struct {
+				name          string
+				expected, got float64
+			}{
+				{"x", 3, pos.GetX()},
+				{"y", 6, pos.GetY()},
+				{"z", 9, pos.GetZ()},
+			}
+
+			for _, test := range tests {
+				if test.got != test.expected {
+					t.Fatalf(
+						"[%T %v] expected %v, got %v",
+						integrator, test.name, test.expected, test.got,
+					)
+				}
+			}
+		}
+	})
+
+	t.Run("energy drift", func(t *testing.T) {
+		eulerDrift := drift(gravity.NewEuler())
+
+		tests := []struct {
+			name     string
+			expected float64
+			got      float64
+		}{
+			{"Leapfrog", 1e-6, drift(gravity.NewLeapfrog())},
+			{"VelocityVerlet", 1e-6, drift(gravity.NewVelocityVerlet())},
+			{"RK4", 1e-10, drift(gravity.NewRK4())},
+		}
+
+		for _, test := range tests {
+			if test.got > test.expected || test.got > eulerDrift {
+				t.Fatalf(
+					"[%v] expected drift below %v (Euler %v), got %v",
+					test.name, test.expected, eulerDrift, test.got,
+				)
+			}
+		}
+	})
+
+	t.Run("pinned bodies", func(t *testing.T) {
+		integrators := []gravity.Integrator{
+			gravity.NewEuler(),
+			gravity.NewLeapfrog(),
+			gravity.NewVelocityVerlet(),
+			gravity.NewRK4(),
+			gravity.NewDormandPrince(),
+		}
+
+		for _, integrator := range integrators {
+			sun, _ := gravity.NewBody("Sun", 1.5e+11, 1, 2, 3)
+			sun.SetPinned(true)
+			planet, _ := gravity.NewBody("Planet", 1e+10, 11, 2, 3)
+			system, _ := gravity.NewSystemWithIntegrator(integrator, sun, planet)
+			system.SetCircularOrbit(planet, sun, gravity.NewPoint(0, 0, 1))
+
+			for i := 0; i < 10; i++ {
+				system.Step(0.1)
+			}
+
+			if got := sun.GetPosition(); got.Diff(gravity.NewPoint(1, 2, 3)).Magnitude() != 0 {
+				t.Fatalf("[%T] expected Sun to stay, got %v", integrator, got)
+			}
+
+			if got := sun.GetVelocity().Magnitude(); got != 0 {
+				t.Fatalf("[%T] expected Sun at rest, got %v", integrator, sun.GetVelocity())
+			}
+
+			if got := planet.GetPosition().GetX(); got == 11 {
+				t.Fatalf("[%T] expected planet to move", integrator)
+			}
+		}
+	})
+}
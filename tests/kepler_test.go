@@ -0,0 +1,143 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	gravity "github.com/cacilhas/gravity/system"
+)
+
+func TestKepler(t *testing.T) {
+	angle := func(a, b float64) float64 {
+		return math.Abs(math.Remainder(a-b, 2*math.Pi))
+	}
+
+	roundTrip := func(t *testing.T, el gravity.Elements) {
+		sun, _ := gravity.NewBody("Sun", 2e+30, 1e+9, -2e+9, 3e+8)
+		sun.SetInertia(gravity.NewPoint(1e+33, 0, -2e+33))
+		planet, _ := gravity.NewBody("Planet", 6e+24, 0, 0, 0)
+		system, _ := gravity.NewSystem(sun, planet)
+
+		if err := system.SetElements(planet, sun, el); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		got, err := system.GetElements(planet, sun)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		tests := []struct {
+			name          string
+			expected, got float64
+		}{
+			{"SemiMajorAxis", 0, math.Abs(got.SemiMajorAxis/el.SemiMajorAxis - 1)},
+			{"Eccentricity", 0, math.Abs(got.Eccentricity - el.Eccentricity)},
+			{"Inclination", 0, angle(got.Inclination, el.Inclination)},
+			{"LongitudeOfAscendingNode", 0, angle(got.LongitudeOfAscendingNode, el.LongitudeOfAscendingNode)},
+			{"ArgumentOfPeriapsis", 0, angle(got.ArgumentOfPeriapsis, el.ArgumentOfPeriapsis)},
+			{"MeanAnomaly", 0, angle(got.MeanAnomaly, el.MeanAnomaly)},
+		}
+
+		for _, test := range tests {
+			if test.got-test.expected > 1e-8 {
+				t.Fatalf(
+					"[%v] expected %+v, got %+v",
+					test.name, el, got,
+				)
+			}
+		}
+	}
+
+	t.Run("elliptic round trip", func(t *testing.T) {
+		for _, e := range []float64{0.0167, 0.2, 0.6, 0.95} {
+			for _, M := range []float64{0.1, 2, 3.1, 5} {
+				roundTrip(t, gravity.Elements{
+					SemiMajorAxis:            1.496e+11,
+					Eccentricity:             e,
+					Inclination:              0.3,
+					LongitudeOfAscendingNode: 1.2,
+					ArgumentOfPeriapsis:      4,
+					MeanAnomaly:              M,
+				})
+			}
+		}
+	})
+
+	t.Run("hyperbolic round trip", func(t *testing.T) {
+		for _, e := range []float64{1.1, 2, 5} {
+			for _, M := range []float64{-3, -0.5, 0.5, 10} {
+				roundTrip(t, gravity.Elements{
+					SemiMajorAxis:            -5e+10,
+					Eccentricity:             e,
+					Inclination:              2.5,
+					LongitudeOfAscendingNode: 0.4,
+					ArgumentOfPeriapsis:      1,
+					MeanAnomaly:              M,
+				})
+			}
+		}
+	})
+
+	t.Run("parabolic round trip", func(t *testing.T) {
+		for _, M := range []float64{-2, 0.3, 4} {
+			roundTrip(t, gravity.Elements{
+				SemiMajorAxis:            3e+10,
+				Eccentricity:             1,
+				Inclination:              1,
+				LongitudeOfAscendingNode: 5,
+				ArgumentOfPeriapsis:      2,
+				MeanAnomaly:              M,
+			})
+		}
+	})
+
+	t.Run("circular orbit", func(t *testing.T) {
+		sun, _ := gravity.NewBody("Sun", 2e+30, 0, 0, 0)
+		planet, _ := gravity.NewBody("Planet", 1, 0, 0, 0)
+		system, _ := gravity.NewSystem(sun, planet)
+		system.SetElements(planet, sun, gravity.Elements{SemiMajorAxis: 1e+11})
+		speed := math.Sqrt(gravity.G * (2e+30 + 1) / 1e+11)
+
+		tests := []struct {
+			name          string
+			expected, got float64
+		}{
+			{"distance", 1e+11, planet.GetPosition().Magnitude()},
+			{"speed", speed, planet.GetInertia().Magnitude()},
+			{"radial speed", 0, planet.GetPosition().Dot(planet.GetInertia())},
+		}
+
+		for _, test := range tests {
+			if math.Abs(test.got-test.expected) > 1e-9*test.expected+1e-3 {
+				t.Fatalf(
+					"[SetElements %v] expected %v, got %v",
+					test.name, test.expected, test.got,
+				)
+			}
+		}
+	})
+
+	t.Run("invalid elements", func(t *testing.T) {
+		sun, _ := gravity.NewBody("Sun", 2e+30, 0, 0, 0)
+		planet, _ := gravity.NewBody("Planet", 1, 0, 0, 0)
+		system, _ := gravity.NewSystem(sun, planet)
+
+		tests := []gravity.Elements{
+			{SemiMajorAxis: 1, Eccentricity: -0.1},
+			{SemiMajorAxis: -1, Eccentricity: 0.5},
+			{SemiMajorAxis: 1, Eccentricity: 1.5},
+			{SemiMajorAxis: 0, Eccentricity: 1},
+		}
+
+		for _, el := range tests {
+			if err := system.SetElements(planet, sun, el); err == nil {
+				t.Fatalf("[SetElements %+v] error not raised", el)
+			}
+		}
+
+		if _, err := system.GetElements(planet, sun); err == nil {
+			t.Fatal("[GetElements] error not raised for coincident bodies")
+		}
+	})
+}
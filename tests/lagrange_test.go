@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	gravity "github.com/cacilhas/gravity/system"
+)
+
+func TestLagrange(t *testing.T) {
+	// Earth-Moon mass ratio, with G(m1 + m2) = 1, unit separation and
+	// unit angular velocity
+	mu := 0.01215
+	binary := func() (gravity.System, gravity.Body, gravity.Body) {
+		earth, _ := gravity.NewBodyWithVelocity("Earth", (1-mu)/gravity.G, -mu, 0, 0, 0, -mu, 0)
+		moon, _ := gravity.NewBodyWithVelocity("Moon", mu/gravity.G, 1-mu, 0, 0, 0, 1-mu, 0)
+		system, _ := gravity.NewSystemWithIntegrator(gravity.NewRK4(), earth, moon)
+		return system, earth, moon
+	}
+
+	t.Run("#LagrangePoints", func(t *testing.T) {
+		system, earth, moon := binary()
+		points, err := system.LagrangePoints(earth, moon)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		tests := []struct {
+			name          string
+			expected, got gravity.Point
+		}{
+			{"L1", gravity.NewPoint(0.83692, 0, 0), points[0]},
+			{"L2", gravity.NewPoint(1.15568, 0, 0), points[1]},
+			{"L3", gravity.NewPoint(-1.00506, 0, 0), points[2]},
+			{"L4", gravity.NewPoint(0.5-mu, math.Sqrt(3)/2, 0), points[3]},
+			{"L5", gravity.NewPoint(0.5-mu, -math.Sqrt(3)/2, 0), points[4]},
+		}
+
+		for _, test := range tests {
+			if test.got.Diff(test.expected).Magnitude() > 1e-5 {
+				t.Fatalf(
+					"[LagrangePoints %v] expected %v, got %v",
+					test.name, test.expected, test.got,
+				)
+			}
+		}
+
+		still, _ := gravity.NewBodyWithVelocity("Still", 1, 1, 0, 0, 0, -mu, 0)
+		if _, err := system.LagrangePoints(earth, still); err == nil {
+			t.Fatal("[LagrangePoints] error not raised for degenerate orbit")
+		}
+	})
+
+	t.Run("#GetRotatingFrame", func(t *testing.T) {
+		system, earth, moon := binary()
+		frame, _ := system.GetRotatingFrame(earth, moon)
+
+		if frame.Rate != 1 || frame.Separation != 1 || frame.MassRatio != mu {
+			t.Fatalf("[GetRotatingFrame] expected unit frame, got %v", frame)
+		}
+
+		pos, vel := frame.ToFrame(moon.GetPosition(), moon.GetVelocity())
+		if pos.Diff(gravity.NewPoint(1-mu, 0, 0)).Magnitude() > 1e-12 || vel.Magnitude() > 1e-12 {
+			t.Fatalf("[RotatingFrame.ToFrame] expected Moon at rest, got %v %v", pos, vel)
+		}
+
+		pos, vel = frame.FromFrame(gravity.NewPoint(1, 2, 3), gravity.NewPoint(4, 5, 6))
+		pos, vel = frame.ToFrame(pos, vel)
+		if pos.Diff(gravity.NewPoint(1, 2, 3)).Magnitude() > 1e-12 || vel.Diff(gravity.NewPoint(4, 5, 6)).Magnitude() > 1e-12 {
+			t.Fatalf("[RotatingFrame.FromFrame] round trip failed, got %v %v", pos, vel)
+		}
+	})
+
+	t.Run("Trojan", func(t *testing.T) {
+		system, earth, moon := binary()
+		points, _ := system.LagrangePoints(earth, moon)
+		frame, _ := system.GetRotatingFrame(earth, moon)
+		_, vel := frame.FromFrame(gravity.NewPoint(0.5-mu, math.Sqrt(3)/2, 0), gravity.NewPoint(0, 0, 0))
+		trojan := gravity.NewTestParticle("Trojan", points[3].GetX(), points[3].GetY(), points[3].GetZ())
+		trojan.SetVelocity(vel)
+		system.AddBody(trojan)
+
+		c0, _ := system.JacobiConstant(trojan, earth, moon)
+		if expected := 3 - mu*(1-mu); math.Abs(c0-expected) > 1e-12 {
+			t.Fatalf("[JacobiConstant] expected %v, got %v", expected, c0)
+		}
+
+		for i := 0; i < 1000; i++ {
+			system.Step(2 * math.Pi / 1000)
+		}
+
+		frame, _ = system.GetRotatingFrame(earth, moon)
+		pos, _ := frame.ToFrame(trojan.GetPosition(), trojan.GetVelocity())
+		if d := pos.Diff(gravity.NewPoint(0.5-mu, math.Sqrt(3)/2, 0)).Magnitude(); d > 1e-6 {
+			t.Fatalf("expected Trojan to stay at L4, got %v off", d)
+		}
+
+		if c, _ := system.JacobiConstant(trojan, earth, moon); math.Abs(c-c0) > 1e-9 {
+			t.Fatalf("[JacobiConstant] expected %v conserved, got %v", c0, c)
+		}
+	})
+
+	t.Run("#CoRotatingSystem", func(t *testing.T) {
+		system, earth, moon := binary()
+		// a tadpole orbit displaced from L4
+		frame, _ := system.GetRotatingFrame(earth, moon)
+		pos, vel := frame.FromFrame(gravity.NewPoint(0.5-mu+0.02, math.Sqrt(3)/2, 0), gravity.NewPoint(0, 0.01, 0.005))
+		probe := gravity.NewTestParticle("Probe", pos.GetX(), pos.GetY(), pos.GetZ())
+		probe.SetVelocity(vel)
+		c0, _ := system.JacobiConstant(probe, earth, moon)
+
+		rotating, err := system.CoRotatingSystem(earth, moon, probe)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !rotating.GetBody("Earth").IsPinned() || !rotating.GetBody("Moon").IsPinned() {
+			t.Fatal("expected primaries pinned in the co-rotating frame")
+		}
+
+		particle := rotating.GetBody("Probe")
+		jacobi := func() float64 {
+			pos, vel := particle.GetPosition(), particle.GetVelocity()
+			r1 := pos.Diff(gravity.NewPoint(-mu, 0, 0)).Magnitude()
+			r2 := pos.Diff(gravity.NewPoint(1-mu, 0, 0)).Magnitude()
+			rho2 := pos.GetX()*pos.GetX() + pos.GetY()*pos.GetY()
+			return 2*((1-mu)/r1+mu/r2) + rho2 - vel.Dot(vel)
+		}
+		if got := jacobi(); math.Abs(got-c0) > 1e-12 {
+			t.Fatalf("[CoRotatingSystem] expected Jacobi constant %v, got %v", c0, got)
+		}
+
+		start := particle.GetPosition()
+		for i := 0; i < 5000; i++ {
+			if err := rotating.Step(2 * math.Pi / 1000); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := jacobi(); math.Abs(got-c0) > 1e-9 {
+				t.Fatalf("[CoRotatingSystem] expected Jacobi constant %v conserved, got %v at step %v", c0, got, i)
+			}
+		}
+		if d := particle.GetPosition().Diff(start).Magnitude(); d < 1e-3 {
+			t.Fatalf("expected the probe to move in the frame, got %v", d)
+		}
+	})
+}
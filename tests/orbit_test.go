@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	gravity "github.com/cacilhas/gravity/system"
+)
+
+func TestOrbit(t *testing.T) {
+	// GM ≈ 10 and circular speed ≈ 1 at distance 10
+	mass := 10 / gravity.G
+
+	t.Run("#SetCircularOrbit", func(t *testing.T) {
+		sun, _ := gravity.NewBodyWithVelocity("Sun", mass, 0, 0, 0, 0, 0, 3)
+		planet, _ := gravity.NewBody("Planet", 1, 0, 10, 0)
+		system, _ := gravity.NewSystem(sun, planet)
+
+		if err := system.SetCircularOrbit(planet, sun, gravity.NewPoint(1, 0, 0)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		el, _ := system.GetElements(planet, sun)
+		tests := []struct {
+			name          string
+			expected, got float64
+		}{
+			{"Eccentricity", 0, el.Eccentricity},
+			{"SemiMajorAxis", 10, el.SemiMajorAxis},
+			{"Inclination", math.Pi / 2, el.Inclination},
+			{"velocity Z", 3 + 1, planet.GetVelocity().GetZ()},
+		}
+
+		for _, test := range tests {
+			if math.Abs(test.got-test.expected) > 1e-6 {
+				t.Fatalf(
+					"[SetCircularOrbit %v] expected %v, got %v",
+					test.name, test.expected, test.got,
+				)
+			}
+		}
+
+		if err := system.SetCircularOrbit(planet, sun, gravity.NewPoint(0, 1, 0)); err == nil {
+			t.Fatal("[SetCircularOrbit] error not raised for normal along separation")
+		}
+	})
+
+	t.Run("barycentre", func(t *testing.T) {
+		star1, _ := gravity.NewBody("Star 1", mass/2, -1, 0, 0)
+		star2, _ := gravity.NewBody("Star 2", mass/2, 1, 0, 0)
+		planet, _ := gravity.NewBody("Planet", 1, 0, 10, 0)
+		system, _ := gravity.NewSystem(star1, star2, planet)
+
+		if err := system.SetCircularOrbit(planet, nil, gravity.NewPoint(0, 0, 1)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got := planet.GetVelocity().GetX(); math.Abs(got+1) > 1e-6 {
+			t.Fatalf("expected velocity X -1, got %v", got)
+		}
+
+		lonely, _ := gravity.NewSystem(planet)
+		if err := lonely.SetCircularOrbit(planet, nil, gravity.NewPoint(0, 0, 1)); err == nil {
+			t.Fatal("error not raised for lonely body")
+		}
+	})
+
+	t.Run("velocities and period", func(t *testing.T) {
+		sun, _ := gravity.NewBody("Sun", mass, 0, 0, 0)
+		planet, _ := gravity.NewBody("Planet", 1e-20, 10, 0, 0)
+		system, _ := gravity.NewSystem(sun, planet)
+		system.SetCircularOrbit(planet, sun, gravity.NewPoint(0, 0, 1))
+
+		circular, _ := system.CircularVelocity(planet, sun)
+		escape, _ := system.EscapeVelocity(planet, nil)
+		period, _ := system.OrbitalPeriod(planet, sun)
+
+		tests := []struct {
+			name          string
+			expected, got float64
+		}{
+			{"CircularVelocity", 1, circular},
+			{"EscapeVelocity", math.Sqrt(2), escape},
+			{"OrbitalPeriod", 20 * math.Pi, period},
+		}
+
+		for _, test := range tests {
+			if math.Abs(test.got-test.expected) > 1e-9 {
+				t.Fatalf(
+					"[System.%v] expected %v, got %v",
+					test.name, test.expected, test.got,
+				)
+			}
+		}
+
+		planet.SetVelocity(gravity.NewPoint(0, 2, 0))
+		if _, err := system.OrbitalPeriod(planet, sun); err == nil {
+			t.Fatal("[OrbitalPeriod] error not raised for unbound body")
+		}
+	})
+}
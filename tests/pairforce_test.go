@@ -0,0 +1,204 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	gravity "github.com/cacilhas/gravity/system"
+)
+
+func TestPairForce(t *testing.T) {
+	pair := func(d float64) (gravity.Body, gravity.Body) {
+		body1, _ := gravity.NewBody("Sample 1", 2, 0, 0, 0)
+		body2, _ := gravity.NewBody("Sample 2", 3, d, 0, 0)
+		return body1, body2
+	}
+
+	t.Run("#SetPairForce", func(t *testing.T) {
+		system, _ := gravity.NewSystem()
+
+		if got := system.GetPairForce(); got != gravity.NewNewtonian() {
+			t.Fatalf("expected Newtonian as default, got %v", got)
+		}
+
+		if err := system.SetPairForce(nil); err == nil {
+			t.Fatal("error not raised")
+		}
+
+		system.SetLegacyGravity(true)
+		if got := system.GetPairForce(); got != gravity.NewLegacyNewtonian() {
+			t.Fatalf("expected legacy law, got %v", got)
+		}
+
+		system.SetLegacyGravity(false)
+		if got := system.GetPairForce(); got != gravity.NewNewtonian() {
+			t.Fatalf("expected Newtonian law, got %v", got)
+		}
+	})
+
+	t.Run("invalid laws", func(t *testing.T) {
+		if f, err := gravity.NewYukawa(0); f != nil || err == nil {
+			t.Fatalf("[NewYukawa] expected error, got %v", f)
+		}
+
+		if f, err := gravity.NewMOND(-1); f != nil || err == nil {
+			t.Fatalf("[NewMOND] expected error, got %v", f)
+		}
+
+		if f, err := gravity.NewCoulomb(math.Inf(1)); f != nil || err == nil {
+			t.Fatalf("[NewCoulomb] expected error, got %v", f)
+		}
+
+		if f, err := gravity.NewSumForce(gravity.NewNewtonian(), nil); f != nil || err == nil {
+			t.Fatalf("[NewSumForce] expected error, got %v", f)
+		}
+	})
+
+	t.Run("magnitudes", func(t *testing.T) {
+		yukawa, _ := gravity.NewYukawa(2)
+		mond, _ := gravity.NewMOND(1e+12)
+		coulomb, _ := gravity.NewCoulomb(1)
+		body1, body2 := pair(2)
+		body1.SetCharge(3)
+		body2.SetCharge(4)
+		gN := 3.0 / 4
+
+		tests := []struct {
+			name          string
+			expected, got float64
+		}{
+			{"Newtonian", 6.0 / 4, gravity.NewNewtonian().Force(body1, body2, 1, 0).GetX()},
+			{"Yukawa", 6 * math.Exp(-1) * (1.0/4 + 1.0/4), yukawa.Force(body1, body2, 1, 0).GetX()},
+			{"deep MOND", 2 * math.Sqrt(gN*1e+12), mond.Force(body1, body2, 1, 0).GetX()},
+			{"Coulomb", -12.0 / 4, coulomb.Force(body1, body2, 1, 0).GetX()},
+		}
+
+		for _, test := range tests {
+			if math.Abs(test.got/test.expected-1) > 1e-6 {
+				t.Fatalf(
+					"[%v] expected %v, got %v",
+					test.name, test.expected, test.got,
+				)
+			}
+		}
+	})
+
+	t.Run("potentials", func(t *testing.T) {
+		yukawa, _ := gravity.NewYukawa(2)
+		coulomb, _ := gravity.NewCoulomb(1)
+		sum, _ := gravity.NewSumForce(gravity.NewNewtonian(), coulomb)
+		laws := []gravity.PairForce{gravity.NewNewtonian(), yukawa, coulomb, sum}
+
+		for _, law := range laws {
+			potential := law.(gravity.Potential)
+			for _, softening := range []float64{0, 1} {
+				body1, near := pair(2 - 1e-6)
+				_, far := pair(2 + 1e-6)
+				body1.SetCharge(3)
+				near.SetCharge(-4)
+				far.SetCharge(-4)
+				_, middle := pair(2)
+				middle.SetCharge(-4)
+
+				expected := -(potential.Potential(body1, far, 1, softening) -
+					potential.Potential(body1, near, 1, softening)) / 2e-6
+				got := law.Force(middle, body1, 1, softening).GetX()
+
+				if math.Abs(got-expected) > 1e-6*math.Abs(expected) {
+					t.Fatalf(
+						"[%T ε=%v] expected force %v from potential, got %v",
+						law, softening, expected, got,
+					)
+				}
+			}
+		}
+	})
+
+	t.Run("system potential", func(t *testing.T) {
+		mond, _ := gravity.NewMOND(1)
+		coulomb, _ := gravity.NewCoulomb(1)
+		body1, body2 := pair(2)
+		body1.SetCharge(3)
+		body2.SetCharge(4)
+		system, _ := gravity.NewSystem(body1, body2)
+
+		system.SetPairForce(coulomb)
+		if got := system.PotentialEnergy(); got != 6 {
+			t.Fatalf("expected Coulomb potential 6, got %v", got)
+		}
+
+		system.SetPairForce(mond)
+		if got := system.PotentialEnergy(); !math.IsNaN(got) {
+			t.Fatalf("expected no potential for MOND, got %v", got)
+		}
+	})
+
+	t.Run("asymmetric MOND", func(t *testing.T) {
+		mond, _ := gravity.NewMOND(1e-3)
+		sum, _ := gravity.NewSumForce(gravity.NewNewtonian(), mond)
+		if a, ok := sum.(gravity.Antisymmetric); !ok || a.IsAntisymmetric() {
+			t.Fatal("[SumForce.IsAntisymmetric] expected false with MOND")
+		}
+
+		// step returns the velocities of a heavy and a light body, named in
+		// either order, after one step
+		step := func(solver gravity.Solver, heavy, light string) (float64, float64) {
+			body1, _ := gravity.NewBody(heavy, 1e+10, 0, 0, 0)
+			body2, _ := gravity.NewBody(light, 1, 10, 0, 0)
+			system, _ := gravity.NewSystem(body1, body2)
+			system.SetSolver(solver)
+			system.SetPairForce(mond)
+			system.Step(1)
+			return body1.GetVelocity().GetX(), body2.GetVelocity().GetX()
+		}
+
+		body1, _ := gravity.NewBody("Heavy", 1e+10, 0, 0, 0)
+		body2, _ := gravity.NewBody("Light", 1, 10, 0, 0)
+		expected := [2]float64{
+			mond.Force(body1, body2, gravity.G, 0).GetX() / 1e+10,
+			mond.Force(body2, body1, gravity.G, 0).GetX(),
+		}
+		barnesHut, _ := gravity.NewBarnesHutSolver(0.5)
+		tests := []struct {
+			name         string
+			solver       gravity.Solver
+			heavy, light string
+		}{
+			{"pairwise A, B", gravity.NewPairwiseSolver(), "A", "B"},
+			{"pairwise B, A", gravity.NewPairwiseSolver(), "B", "A"},
+			{"Barnes-Hut A, B", barnesHut, "A", "B"},
+			{"Barnes-Hut B, A", barnesHut, "B", "A"},
+		}
+		for _, test := range tests {
+			v1, v2 := step(test.solver, test.heavy, test.light)
+			if math.Abs(v1/expected[0]-1) > 1e-12 || math.Abs(v2/expected[1]-1) > 1e-12 {
+				t.Fatalf("[%v] expected %v, got %v", test.name, expected, [2]float64{v1, v2})
+			}
+		}
+	})
+
+	t.Run("charged system", func(t *testing.T) {
+		coulomb, _ := gravity.NewCoulomb(1)
+		body1, body2 := pair(2)
+		body1.SetCharge(1)
+		body2.SetCharge(1)
+		system, _ := gravity.NewSystem(body1, body2)
+		system.SetPairForce(coulomb)
+		system.Step(1)
+
+		if got := body2.GetVelocity().GetX(); math.Abs(got-1.0/12) > 1e-12 {
+			t.Fatalf("expected repulsion of 1/12, got %v", got)
+		}
+
+		dust := gravity.NewTestParticle("Dust", 0, 1, 0)
+		dust.SetCharge(1)
+		if got := dust.GetCharge(); got != 0 {
+			t.Fatalf("[Body.SetCharge] expected neutral test particle, got %v", got)
+		}
+		system.AddBody(dust)
+		system.Step(1)
+		if got := dust.GetVelocity().Magnitude(); got != 0 {
+			t.Fatalf("expected test particle not to feel charges, got %v", dust.GetVelocity())
+		}
+	})
+}
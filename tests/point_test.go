@@ -234,4 +234,37 @@ func TestPoint(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("#Dot", func(t *testing.T) {
+		point1 := gravity.NewPoint(1, 2, 3)
+		point2 := gravity.NewPoint(4, 5, 6)
+
+		if got := point1.Dot(point2); got != 32 {
+			t.Fatalf("[Point.Dot] expected 32, got %v", got)
+		}
+	})
+
+	t.Run("#Cross", func(t *testing.T) {
+		point1 := gravity.NewPoint(1, 2, 3)
+		point2 := gravity.NewPoint(4, 5, 6)
+		pointR := point1.Cross(point2)
+
+		tests := []struct {
+			name          string
+			expected, got float64
+		}{
+			{"x", -3, pointR.GetX()},
+			{"y", 6, pointR.GetY()},
+			{"z", -3, pointR.GetZ()},
+		}
+
+		for _, test := range tests {
+			if test.got != test.expected {
+				t.Fatalf(
+					"[Point.Cross %v] expected %v, got %v",
+					test.name, test.expected, test.got,
+				)
+			}
+		}
+	})
 }
@@ -0,0 +1,156 @@
+package tests
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	gravity "github.com/cacilhas/gravity/system"
+)
+
+func TestPrecision(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		system, _ := gravity.NewSystem()
+		if got := system.GetPrecision(); got != gravity.DoublePrecision {
+			t.Fatalf("[System.GetPrecision] expected %v, got %v", gravity.DoublePrecision, got)
+		}
+	})
+
+	t.Run("#SetPrecision", func(t *testing.T) {
+		tests := []struct {
+			precision gravity.Precision
+			name      string
+			valid     bool
+		}{
+			{gravity.DoublePrecision, "double", true},
+			{gravity.CompensatedPrecision, "compensated", true},
+			{gravity.DoubleDoublePrecision, "double-double", true},
+			{gravity.Precision(-1), "Precision(-1)", false},
+			{gravity.Precision(3), "Precision(3)", false},
+		}
+		for _, test := range tests {
+			system, _ := gravity.NewSystem()
+			err := system.SetPrecision(test.precision)
+			if (err == nil) != test.valid {
+				t.Fatalf("[System.SetPrecision] expected valid %v, got %v", test.valid, err)
+			}
+			if got := test.precision.String(); got != test.name {
+				t.Fatalf("[Precision.String] expected %v, got %v", test.name, got)
+			}
+			if test.valid && system.GetPrecision() != test.precision {
+				t.Fatalf("[System.GetPrecision] expected %v, got %v", test.precision, system.GetPrecision())
+			}
+		}
+	})
+
+	// drift moves a lone particle far from the origin in tiny steps whose
+	// increments are not representable, and returns its position error
+	// against the exact sum
+	const (
+		x0    = 1e+10
+		speed = 3.0
+		dt    = 0.1
+		steps = 10000
+	)
+	exact := new(big.Float).SetPrec(256).SetFloat64(speed)
+	exact.Mul(exact, new(big.Float).SetPrec(256).SetFloat64(dt))
+	exact.Mul(exact, new(big.Float).SetPrec(256).SetInt64(steps))
+	exact.Add(exact, new(big.Float).SetPrec(256).SetFloat64(x0))
+	expected, _ := exact.Float64()
+	ulp := math.Nextafter(expected, math.Inf(1)) - expected
+
+	drift := func(integrator gravity.Integrator, precision gravity.Precision, legacy bool) float64 {
+		system, _ := gravity.NewSystemWithIntegrator(integrator)
+		system.SetPrecision(precision)
+		system.SetLegacyGravity(legacy)
+		b := gravity.NewTestParticle("Probe", x0, 0, 0)
+		b.SetVelocity(gravity.NewPoint(speed, 0, 0))
+		system.AddBody(b)
+		for i := 0; i < steps; i++ {
+			system.Step(dt)
+		}
+		return math.Abs(b.GetPosition().GetX() - expected)
+	}
+
+	t.Run("accumulation", func(t *testing.T) {
+		integrators := map[string]func() gravity.Integrator{
+			"euler":    gravity.NewEuler,
+			"leapfrog": gravity.NewLeapfrog,
+			"verlet":   gravity.NewVelocityVerlet,
+			"rk4":      gravity.NewRK4,
+		}
+		for name, integrator := range integrators {
+			for _, legacy := range []bool{false, true} {
+				double := drift(integrator(), gravity.DoublePrecision, legacy)
+				if double <= 2*ulp {
+					t.Fatalf("[%v double] expected error above %v, got %v", name, 2*ulp, double)
+				}
+				for _, precision := range []gravity.Precision{gravity.CompensatedPrecision, gravity.DoubleDoublePrecision} {
+					if got := drift(integrator(), precision, legacy); got > ulp {
+						t.Fatalf("[%v %v] expected error under %v, got %v", name, precision, ulp, got)
+					}
+				}
+			}
+		}
+	})
+
+	t.Run("unsupported integrators", func(t *testing.T) {
+		wh, _ := gravity.NewWisdomHolman(gravity.Jacobi)
+		integrators := []gravity.Integrator{
+			gravity.NewDormandPrince(),
+			gravity.NewStepDoubling(gravity.NewRK4()),
+			gravity.NewLegacyEuler(),
+			wh,
+		}
+		for _, integrator := range integrators {
+			system, _ := gravity.NewSystemWithIntegrator(integrator)
+			system.AddBody(gravity.NewTestParticle("Probe", x0, 0, 0))
+			system.SetPrecision(gravity.CompensatedPrecision)
+			if err := system.Step(dt); err == nil {
+				t.Fatalf("[%T] expected Step error", integrator)
+			}
+			if got := system.GetTime(); got != 0 {
+				t.Fatalf("[%T] expected no time elapsed, got %v", integrator, got)
+			}
+
+			system.SetPrecision(gravity.DoublePrecision)
+			if err := system.Step(dt); err != nil {
+				t.Fatalf("[%T] unexpected error once reset: %v", integrator, err)
+			}
+		}
+
+		// adaptive steps never run on the arrays
+		system, _ := gravity.NewSystemWithIntegrator(gravity.NewRK4())
+		system.AddBody(gravity.NewTestParticle("Probe", x0, 0, 0))
+		system.SetPrecision(gravity.DoubleDoublePrecision)
+		if err := system.Step(dt); err != nil {
+			t.Fatalf("unexpected Step error: %v", err)
+		}
+		if _, err := system.Advance(1, 1e-6); err == nil {
+			t.Fatal("expected Advance error")
+		}
+		system.SetPrecision(gravity.DoublePrecision)
+		if _, err := system.Advance(1, 1e-6); err != nil {
+			t.Fatalf("unexpected error once reset: %v", err)
+		}
+	})
+
+	t.Run("reset on SetPosition", func(t *testing.T) {
+		system, _ := gravity.NewSystemWithIntegrator(gravity.NewLeapfrog())
+		system.SetPrecision(gravity.DoubleDoublePrecision)
+		b := gravity.NewTestParticle("Probe", x0, 0, 0)
+		b.SetVelocity(gravity.NewPoint(speed, 0, 0))
+		system.AddBody(b)
+		for i := 0; i < 7; i++ {
+			system.Step(dt)
+		}
+
+		// a stale compensation, about an ulp of x0, would show near zero
+		b.SetPosition(gravity.NewPoint(0, 0, 0))
+		b.SetVelocity(gravity.NewPoint(1, 0, 0))
+		system.Step(dt)
+		if got, expected := b.GetPosition().GetX(), dt; got != expected {
+			t.Fatalf("[System.Step] expected %v, got %v", expected, got)
+		}
+	})
+}
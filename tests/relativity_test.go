@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	gravity "github.com/cacilhas/gravity/system"
+)
+
+func TestRelativity(t *testing.T) {
+	// GM/(c²a) = 1e-4, so the orbit precesses fast enough to measure
+	precession := func(relativistic bool) (float64, float64) {
+		const orbits = 10
+		gm := 1e-4 * gravity.SpeedOfLight * gravity.SpeedOfLight * 1.5e+7
+		el := gravity.Elements{SemiMajorAxis: 1.5e+7, Eccentricity: 0.3}
+		sun, _ := gravity.NewBody("Sun", gm/gravity.G, 0, 0, 0)
+		probe, _ := gravity.NewBody("Probe", 1, 0, 0, 0)
+		system, _ := gravity.NewSystemWithIntegrator(gravity.NewDormandPrince(), sun, probe)
+		system.SetElements(probe, sun, el)
+		if relativistic {
+			system.SetPostNewtonian(sun)
+		}
+
+		period, _ := system.OrbitalPeriod(probe, sun)
+		if _, err := system.Advance(orbits*period, 1e-12); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		got, _ := system.GetElements(probe, sun)
+		expected := 6 * math.Pi * gm /
+			(gravity.SpeedOfLight * gravity.SpeedOfLight * el.SemiMajorAxis * (1 - el.Eccentricity*el.Eccentricity))
+		return math.Remainder(got.ArgumentOfPeriapsis, 2*math.Pi) / orbits, expected
+	}
+
+	t.Run("#SetPostNewtonian", func(t *testing.T) {
+		sun, _ := gravity.NewBody("Sun", 1, 0, 0, 0)
+		system, _ := gravity.NewSystem(sun)
+
+		if got := system.GetPostNewtonian(); got != nil {
+			t.Fatalf("expected Newtonian system, got %v", got)
+		}
+
+		system.SetPostNewtonian(sun)
+		if got := system.GetPostNewtonian(); got != sun {
+			t.Fatalf("expected %v, got %v", sun, got)
+		}
+	})
+
+	t.Run("perihelion precession", func(t *testing.T) {
+		got, expected := precession(true)
+
+		if math.Abs(got/expected-1) > 0.05 {
+			t.Fatalf("expected precession of %v rad/orbit, got %v", expected, got)
+		}
+	})
+
+	t.Run("Newtonian orbit keeps still", func(t *testing.T) {
+		got, expected := precession(false)
+
+		if math.Abs(got) > expected/100 {
+			t.Fatalf("expected no precession, got %v rad/orbit", got)
+		}
+	})
+}
@@ -0,0 +1,255 @@
+package tests
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	gravity "github.com/cacilhas/gravity/system"
+)
+
+func TestSolver(t *testing.T) {
+	// the exact inverse-square law, so accuracy does not depend on Body.Grav
+	grav := func(b1, b2 gravity.Body) gravity.Point {
+		diff := b2.GetPosition().Diff(b1.GetPosition())
+		d := diff.Magnitude()
+		return diff.Mul(gravity.G * b1.GetMass() * b2.GetMass() / (d * d * d))
+	}
+
+	cluster := func(count int) []gravity.Body {
+		random := rand.New(rand.NewSource(42))
+		bodies := make([]gravity.Body, count)
+		for i := range bodies {
+			bodies[i], _ = gravity.NewBody(
+				fmt.Sprintf("Body %v", i),
+				1e+20+random.Float64()*1e+22,
+				random.NormFloat64()*1e+9,
+				random.NormFloat64()*1e+9,
+				random.NormFloat64()*1e+9,
+			)
+		}
+		return bodies
+	}
+
+	// relativeError returns the RMS of the per-body relative errors
+	relativeError := func(expected, got []gravity.Point) float64 {
+		var sum float64
+		for i := range expected {
+			e := expected[i].Diff(got[i]).Magnitude() / expected[i].Magnitude()
+			sum += e * e
+		}
+		return math.Sqrt(sum / float64(len(expected)))
+	}
+
+	t.Run("invalid opening angle", func(t *testing.T) {
+		for _, theta := range []float64{-1, math.NaN(), math.Inf(1)} {
+			solver, err := gravity.NewBarnesHutSolver(theta)
+
+			if solver != nil {
+				t.Fatalf("[NewBarnesHutSolver] expected no solver, got %v", solver)
+			}
+
+			if err == nil {
+				t.Fatalf("[NewBarnesHutSolver %v] error not raised", theta)
+			}
+		}
+	})
+
+	t.Run("#GetSolver", func(t *testing.T) {
+		system, _ := gravity.NewSystem()
+
+		if got := system.GetSolver(); got != gravity.NewPairwiseSolver() {
+			t.Fatalf("expected pairwise as default, got %v", got)
+		}
+
+		solver, _ := gravity.NewBarnesHutSolver(0.5)
+		if err := system.SetSolver(solver); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got := system.GetSolver(); got != solver {
+			t.Fatalf("expected %v, got %v", solver, got)
+		}
+
+		if err := system.SetSolver(nil); err == nil {
+			t.Fatal("error not raised")
+		}
+	})
+
+	t.Run("workers", func(t *testing.T) {
+		for _, workers := range []int{0, -1} {
+			if solver, err := gravity.NewPairwiseSolverWithWorkers(workers); solver != nil || err == nil {
+				t.Fatalf("[NewPairwiseSolverWithWorkers %v] expected error, got %v", workers, solver)
+			}
+		}
+
+		bodies := append(cluster(200), gravity.NewTestParticle("Dust", 0, 0, 0))
+		serial, _ := gravity.NewPairwiseSolverWithWorkers(1)
+		expected := serial.Accelerations(bodies, grav)
+
+		for _, workers := range []int{2, 3, 8, 500} {
+			solver, _ := gravity.NewPairwiseSolverWithWorkers(workers)
+			if got := relativeError(expected, solver.Accelerations(bodies, grav)); got > 1e-14 {
+				t.Fatalf(
+					"[Pairwise %v workers] expected serial results, got error %v",
+					workers, got,
+				)
+			}
+		}
+	})
+
+	t.Run("Barnes-Hut accuracy", func(t *testing.T) {
+		bodies := cluster(300)
+		exact := gravity.NewPairwiseSolver().Accelerations(bodies, grav)
+
+		tests := []struct {
+			theta, expected float64
+		}{
+			{0, 1e-12},
+			{0.3, 5e-3},
+			{0.5, 2e-2},
+			{1, 1e-1},
+		}
+
+		for _, test := range tests {
+			solver, _ := gravity.NewBarnesHutSolver(test.theta)
+			got := relativeError(exact, solver.Accelerations(bodies, grav))
+			if got > test.expected {
+				t.Fatalf(
+					"[BarnesHut θ=%v] expected error below %v, got %v",
+					test.theta, test.expected, got,
+				)
+			}
+		}
+	})
+
+	t.Run("Barnes-Hut coincident bodies", func(t *testing.T) {
+		body1, _ := gravity.NewBody("Assemble 1", 10, 1, 1, 1)
+		body2, _ := gravity.NewBody("Assemble 2", 10, 1, 1, 1)
+		body3, _ := gravity.NewBody("Assemble 3", 10, 5, 5, 5)
+		solver, _ := gravity.NewBarnesHutSolver(0.5)
+		// the System law, which has no force between coincident bodies
+		newtonian := func(b1, b2 gravity.Body) gravity.Point {
+			return gravity.NewNewtonian().Force(b1, b2, gravity.G, 0)
+		}
+		got := solver.Accelerations([]gravity.Body{body1, body2, body3}, newtonian)
+
+		if len(got) != 3 {
+			t.Fatalf("expected 3 accelerations, got %v", len(got))
+		}
+		for i, acc := range got {
+			for _, c := range []float64{acc.GetX(), acc.GetY(), acc.GetZ()} {
+				if math.IsNaN(c) || math.IsInf(c, 0) {
+					t.Fatalf("expected finite acceleration for body %v, got %v", i+1, acc)
+				}
+			}
+		}
+		// the coincident pair pulls on each other with no force at all, so
+		// both only feel the third body
+		expected := body3.GetPosition().Diff(body1.GetPosition())
+		expected = expected.Mul(gravity.G * body3.GetMass() / math.Pow(expected.Magnitude(), 3))
+		for i, acc := range got[:2] {
+			if err := acc.Diff(expected).Magnitude() / expected.Magnitude(); err > 1e-12 {
+				t.Fatalf("expected acceleration %v for body %v, got %v", expected, i+1, acc)
+			}
+		}
+	})
+
+	t.Run("Barnes-Hut workers", func(t *testing.T) {
+		for _, workers := range []int{0, -1} {
+			if solver, err := gravity.NewBarnesHutSolverWithWorkers(0.5, workers); solver != nil || err == nil {
+				t.Fatalf("[NewBarnesHutSolverWithWorkers %v] expected error, got %v", workers, solver)
+			}
+		}
+		if solver, err := gravity.NewBarnesHutSolverWithWorkers(-1, 2); solver != nil || err == nil {
+			t.Fatalf("[NewBarnesHutSolverWithWorkers] expected error for theta, got %v", solver)
+		}
+
+		bodies := append(cluster(200), gravity.NewTestParticle("Dust", 0, 0, 0))
+		serial, _ := gravity.NewBarnesHutSolverWithWorkers(0.5, 1)
+		expected := serial.Accelerations(bodies, grav)
+
+		for _, workers := range []int{2, 3, 8, 500} {
+			solver, _ := gravity.NewBarnesHutSolverWithWorkers(0.5, workers)
+			if got := relativeError(expected, solver.Accelerations(bodies, grav)); got != 0 {
+				t.Fatalf(
+					"[Barnes-Hut %v workers] expected serial results, got error %v",
+					workers, got,
+				)
+			}
+		}
+	})
+
+	t.Run("Barnes-Hut stepping", func(t *testing.T) {
+		bodies := cluster(50)
+		solver, _ := gravity.NewBarnesHutSolver(0.5)
+		system, _ := gravity.NewSystem(bodies...)
+		system.SetSolver(solver)
+
+		if err := system.Step(1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("test particles", func(t *testing.T) {
+		sun, _ := gravity.NewBody("Sun", 1e+20, 0, 0, 0)
+		planet, _ := gravity.NewBody("Planet", 1e+18, 0, 1e+6, 0)
+		bodies := []gravity.Body{
+			sun,
+			gravity.NewTestParticle("Dust 1", 1e+5, 0, 0),
+			planet,
+			gravity.NewTestParticle("Dust 2", 1e+5, 0, 0),
+		}
+		barnesHut, _ := gravity.NewBarnesHutSolver(0.5)
+		dust, _ := gravity.NewBody("Probe", 1, 1e+5, 0, 0)
+		probe := grav(dust, sun).Add(grav(dust, planet))
+
+		for _, solver := range []gravity.Solver{gravity.NewPairwiseSolver(), barnesHut} {
+			got := solver.Accelerations(bodies, grav)
+
+			tests := []struct {
+				name          string
+				expected, got gravity.Point
+			}{
+				{"Sun", grav(sun, planet).Mul(1e-20), got[0]},
+				{"Dust 1", probe, got[1]},
+				{"Planet", grav(planet, sun).Mul(1e-18), got[2]},
+				{"Dust 2", probe, got[3]},
+			}
+
+			for _, test := range tests {
+				if test.got.Diff(test.expected).Magnitude() > 1e-9*test.expected.Magnitude() {
+					t.Fatalf(
+						"[%T %v] expected %v, got %v",
+						solver, test.name, test.expected, test.got,
+					)
+				}
+			}
+		}
+	})
+
+	t.Run("test particle cloud", func(t *testing.T) {
+		sun, _ := gravity.NewBody("Sun", 1e+20, 0, 0, 0)
+		system, _ := gravity.NewSystem(sun)
+		random := rand.New(rand.NewSource(7))
+		for i := 0; i < 2000; i++ {
+			system.AddBody(gravity.NewTestParticle(
+				fmt.Sprintf("Dust %v", i),
+				random.Float64()*1e+6+1e+5, random.Float64()*1e+6+1e+5, 0,
+			))
+		}
+
+		if err := system.Step(1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got := sun.GetVelocity().Magnitude(); got != 0 {
+			t.Fatalf("expected test particles not to pull the Sun, got %v", got)
+		}
+
+		if got := system.GetBody("Dust 0").GetVelocity().Magnitude(); got == 0 {
+			t.Fatal("expected test particles to feel the Sun")
+		}
+	})
+}
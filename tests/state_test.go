@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	gravity "github.com/cacilhas/gravity/system"
+)
+
+func TestState(t *testing.T) {
+	cluster := func() gravity.System {
+		random := rand.New(rand.NewSource(3))
+		system, _ := gravity.NewSystem()
+		for i := 0; i < 40; i++ {
+			b, _ := gravity.NewBodyWithVelocity(
+				fmt.Sprintf("Body %v", i),
+				1e+20+random.Float64()*1e+22,
+				random.NormFloat64()*1e+9, random.NormFloat64()*1e+9, random.NormFloat64()*1e+9,
+				random.NormFloat64()*1e+3, random.NormFloat64()*1e+3, random.NormFloat64()*1e+3,
+			)
+			system.AddBody(b)
+		}
+		system.AddBody(gravity.NewTestParticle("Dust", 1e+9, 0, 0))
+		system.GetBody("Body 0").SetPinned(true)
+		system.SetSoftening(1e+6)
+		return system
+	}
+
+	integrators := []gravity.Integrator{
+		gravity.NewEuler(),
+		gravity.NewLeapfrog(),
+		gravity.NewVelocityVerlet(),
+		gravity.NewRK4(),
+	}
+
+	t.Run("allocations", func(t *testing.T) {
+		solver, _ := gravity.NewPairwiseSolverWithWorkers(4)
+		for _, integrator := range integrators {
+			for _, parallel := range []bool{false, true} {
+				system := cluster()
+				system.SetIntegrator(integrator)
+				if parallel {
+					system.SetSolver(solver)
+				}
+				system.Step(1)
+
+				got := testing.AllocsPerRun(10, func() {
+					system.Step(1)
+				})
+				if got != 0 {
+					t.Fatalf("[%T parallel=%v] expected no allocation, got %v", integrator, parallel, got)
+				}
+			}
+		}
+	})
+
+	t.Run("generic path", func(t *testing.T) {
+		// a sum of a single Newtonian force is stepped through the Body
+		// interfaces instead of the arrays
+		newtonian, _ := gravity.NewSumForce(gravity.NewNewtonian())
+		for _, integrator := range integrators {
+			fast := cluster()
+			slow := cluster()
+			fast.SetIntegrator(integrator)
+			slow.SetIntegrator(integrator)
+			slow.SetPairForce(newtonian)
+
+			for i := 0; i < 10; i++ {
+				fast.Step(100)
+				slow.Step(100)
+			}
+
+			for name, b := range fast.GetBodies() {
+				expected := slow.GetBody(name).GetPosition()
+				if d := b.GetPosition().Diff(expected).Magnitude(); d > 1e-6 {
+					t.Fatalf("[%T %v] expected %v, got %v", integrator, name, expected, b.GetPosition())
+				}
+			}
+		}
+
+		// test particles left alone feel no force at all, on either path
+		for _, integrator := range integrators {
+			for _, force := range []gravity.PairForce{gravity.NewNewtonian(), newtonian} {
+				system, _ := gravity.NewSystemWithIntegrator(integrator)
+				system.SetPairForce(force)
+				sun, _ := gravity.NewBody("Sun", 1e+20, 0, 0, 0)
+				dust := gravity.NewTestParticle("Dust", 1e+6, 0, 0)
+				system.AddBody(sun)
+				system.AddBody(dust)
+				system.Step(1)
+
+				system.RemoveBody(sun)
+				expected := dust.GetVelocity()
+				system.Step(1)
+				if got := dust.GetVelocity(); got.Diff(expected).Magnitude() != 0 {
+					t.Fatalf("[%T %T] expected dust velocity %v, got %v", integrator, force, expected, got)
+				}
+			}
+		}
+	})
+}
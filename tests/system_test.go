@@ -182,7 +182,7 @@ func TestSystem(t *testing.T) {
 						growing(0, body3.GetPosition().GetY(), 1e-10) &&
 						body3.GetPosition().GetZ() == 0 &&
 						growing(-1.4e-10, body3.GetInertia().GetX(), -1.3e-10) &&
-						growing(3.3e-12, body3.GetInertia().GetY(), 3.4e-12) &&
+						growing(2.3e-12, body3.GetInertia().GetY(), 2.4e-12) &&
 						body3.GetInertia().GetZ() == 0,
 				},
 			}
@@ -194,4 +194,47 @@ func TestSystem(t *testing.T) {
 			}
 		})
 	})
+
+	t.Run("#SetLegacyGravity", func(t *testing.T) {
+		body1, _ := gravity.NewBody("Sun", 100, 0, 0, 0)
+		body2, _ := gravity.NewBody("Body", 2, 10, 10, 0)
+		system, _ := gravity.NewSystem(body1, body2)
+
+		if system.IsLegacyGravity() {
+			t.Fatal("expected system to start with true gravity")
+		}
+
+		system.SetLegacyGravity(true)
+		expected := gravity.LegacyGrav(body2, body1, 0)
+		system.Step(1)
+
+		if got := body2.GetInertia(); got.Diff(expected).Magnitude() > 1e-24 {
+			t.Fatalf("expected legacy inertia %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("#SetSoftening", func(t *testing.T) {
+		body1, _ := gravity.NewBody("Sun", 100, 0, 0, 0)
+		body2, _ := gravity.NewBody("Body", 2, 3, 0, 0)
+		system, _ := gravity.NewSystem(body1, body2)
+
+		if got := system.GetSoftening(); got != 0 {
+			t.Fatalf("expected no softening, got %v", got)
+		}
+
+		if err := system.SetSoftening(-1); err == nil {
+			t.Fatal("error not raised")
+		}
+
+		system.SetSoftening(4)
+		if got := system.GetSoftening(); got != 4 {
+			t.Fatalf("expected softening 4, got %v", got)
+		}
+
+		expected := body2.SoftGrav(body1, 4)
+		system.Step(1)
+		if got := body2.GetInertia(); got.Diff(expected).Magnitude() > 1e-24 {
+			t.Fatalf("expected softened inertia %v, got %v", expected, got)
+		}
+	})
 }
@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	gravity "github.com/cacilhas/gravity/system"
+)
+
+func TestUnits(t *testing.T) {
+	t.Run("#G", func(t *testing.T) {
+		nbody, _ := gravity.NewNBodyUnits(1e+30, 1e+10)
+		henon, _ := gravity.NewHenonUnits(1e+30, -1e+35)
+		gauss := 0.01720209895
+
+		tests := []struct {
+			name          string
+			expected, got float64
+		}{
+			{"SI", gravity.G, gravity.SI.G()},
+			{"Astronomical", gauss * gauss, gravity.Astronomical.G()},
+			{"N-body", 1, nbody.G()},
+			{"Hénon", 1, henon.G()},
+			{"Hénon energy", -0.25, gravity.Convert(-1e+35, gravity.DimEnergy, gravity.SI, henon)},
+		}
+
+		for _, test := range tests {
+			if math.Abs(test.got/test.expected-1) > 1e-4 {
+				t.Fatalf(
+					"[Units.G %v] expected %v, got %v",
+					test.name, test.expected, test.got,
+				)
+			}
+		}
+	})
+
+	t.Run("invalid units", func(t *testing.T) {
+		if _, err := gravity.NewNBodyUnits(0, 1); err == nil {
+			t.Fatal("[NewNBodyUnits] error not raised")
+		}
+
+		if _, err := gravity.NewHenonUnits(1, 1); err == nil {
+			t.Fatal("[NewHenonUnits] error not raised")
+		}
+
+		system, _ := gravity.NewSystem()
+		if err := system.SetUnits(gravity.Units{Name: "broken"}); err == nil {
+			t.Fatal("[SetUnits] error not raised")
+		}
+	})
+
+	t.Run("Convert", func(t *testing.T) {
+		tests := []struct {
+			name          string
+			expected, got float64
+		}{
+			{"AU to m", gravity.AU, gravity.Convert(1, gravity.DimLength, gravity.Astronomical, gravity.SI)},
+			{"s to day", 1, gravity.Convert(86400, gravity.DimTime, gravity.SI, gravity.Astronomical)},
+			{"AU/day to m/s", gravity.AU / gravity.Day, gravity.Convert(1, gravity.DimVelocity, gravity.Astronomical, gravity.SI)},
+			{"point", 2 * gravity.AU, gravity.ConvertPoint(gravity.NewPoint(0, 2, 0), gravity.DimLength, gravity.Astronomical, gravity.SI).GetY()},
+		}
+
+		for _, test := range tests {
+			if math.Abs(test.got/test.expected-1) > 1e-12 {
+				t.Fatalf(
+					"[Convert %v] expected %v, got %v",
+					test.name, test.expected, test.got,
+				)
+			}
+		}
+	})
+
+	t.Run("astronomical system", func(t *testing.T) {
+		sun, _ := gravity.NewBody("Sun", 1, 0, 0, 0)
+		earth, _ := gravity.NewBody("Earth", 3.003e-6, 1, 0, 0)
+		system, _ := gravity.NewSystem(sun, earth)
+
+		if got := system.GetUnits(); got != gravity.SI {
+			t.Fatalf("expected SI as default, got %v", got)
+		}
+
+		system.SetUnits(gravity.Astronomical)
+		system.SetIntegrator(gravity.NewRK4())
+		system.SetCircularOrbit(earth, sun, gravity.NewPoint(0, 0, 1))
+		period, _ := system.OrbitalPeriod(earth, sun)
+
+		if math.Abs(period-365.25) > 0.1 {
+			t.Fatalf("expected a year of 365.25 days, got %v", period)
+		}
+
+		for i := 0; i < 100; i++ {
+			system.Step(period / 100)
+		}
+
+		if got := earth.GetPosition().Diff(gravity.NewPoint(1, 0, 0)).Magnitude(); got > 1e-4 {
+			t.Fatalf("expected Earth back after a year, got %v", earth.GetPosition())
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"testing"
+
+	gravity "github.com/cacilhas/gravity/system"
+)
+
+func TestVector(t *testing.T) {
+	v1 := gravity.Vector{X: 1, Y: 2, Z: 3}
+	v2 := gravity.Vector{X: 4, Y: 5, Z: 6}
+
+	t.Run("operations", func(t *testing.T) {
+		tests := []struct {
+			name          string
+			expected, got gravity.Vector
+		}{
+			{"Add", gravity.Vector{X: 5, Y: 7, Z: 9}, v1.Add(v2)},
+			{"Sub", gravity.Vector{X: -3, Y: -3, Z: -3}, v1.Sub(v2)},
+			{"Mul", gravity.Vector{X: 2, Y: 4, Z: 6}, v1.Mul(2)},
+			{"Cross", gravity.Vector{X: -3, Y: 6, Z: -3}, v1.Cross(v2)},
+		}
+
+		for _, test := range tests {
+			if test.got != test.expected {
+				t.Fatalf(
+					"[Vector.%v] expected %v, got %v",
+					test.name, test.expected, test.got,
+				)
+			}
+		}
+
+		if got := v1.Dot(v2); got != 32 {
+			t.Fatalf("[Vector.Dot] expected 32, got %v", got)
+		}
+
+		if got := (gravity.Vector{X: 3, Y: 4}).Magnitude(); got != 5 {
+			t.Fatalf("[Vector.Magnitude] expected 5, got %v", got)
+		}
+	})
+
+	t.Run("Point view", func(t *testing.T) {
+		point := v1.Point()
+		if point.GetX() != 1 || point.GetY() != 2 || point.GetZ() != 3 {
+			t.Fatalf("[Vector.Point] expected %v, got %v", v1, point)
+		}
+
+		if got := gravity.ToVector(point); got != v1 {
+			t.Fatalf("[ToVector] expected %v, got %v", v1, got)
+		}
+
+		if got := v1.String(); got != point.String() {
+			t.Fatalf("[Vector.String] expected %v, got %v", point.String(), got)
+		}
+	})
+
+	t.Run("Body view", func(t *testing.T) {
+		body, _ := gravity.NewBody("Sample", 2, 1, 2, 3)
+		body.SetVelocityVector(v2)
+
+		if got := body.GetPositionVector(); got != v1 {
+			t.Fatalf("[Body.GetPositionVector] expected %v, got %v", v1, got)
+		}
+
+		if got := body.GetMomentum(); got.GetX() != 8 || got.GetY() != 10 || got.GetZ() != 12 {
+			t.Fatalf("[Body.GetMomentum] expected (8, 10, 12), got %v", got)
+		}
+
+		body.SetPosition(gravity.NewPoint(7, 8, 9))
+		if got := body.GetPositionVector(); got != (gravity.Vector{X: 7, Y: 8, Z: 9}) {
+			t.Fatalf("[Body.SetPosition] expected (7, 8, 9), got %v", got)
+		}
+	})
+}
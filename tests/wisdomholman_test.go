@@ -0,0 +1,244 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	gravity "github.com/cacilhas/gravity/system"
+)
+
+func TestWisdomHolman(t *testing.T) {
+	coordinates := []gravity.Coordinates{gravity.DemocraticHeliocentric, gravity.Jacobi}
+
+	t.Run("NewWisdomHolman", func(t *testing.T) {
+		tests := []struct {
+			coordinates gravity.Coordinates
+			name        string
+			valid       bool
+		}{
+			{gravity.DemocraticHeliocentric, "democratic heliocentric", true},
+			{gravity.Jacobi, "Jacobi", true},
+			{gravity.Coordinates(-1), "Coordinates(-1)", false},
+			{gravity.Coordinates(2), "Coordinates(2)", false},
+		}
+		for _, test := range tests {
+			integrator, err := gravity.NewWisdomHolman(test.coordinates)
+			if (err == nil) != test.valid || (integrator != nil) != test.valid {
+				t.Fatalf("[NewWisdomHolman] expected valid %v, got %v, %v", test.valid, integrator, err)
+			}
+			if got := test.coordinates.String(); got != test.name {
+				t.Fatalf("[Coordinates.String] expected %v, got %v", test.name, got)
+			}
+		}
+	})
+
+	// twoBody steps a single orbit with a tenth of its period, or of 2π/n
+	// for a flyby, and returns the position error against the analytic
+	// orbit; a pinned Sun holds a test particle
+	twoBody := func(t *testing.T, c gravity.Coordinates, units gravity.Units, el gravity.Elements, pinned bool) float64 {
+		integrator, _ := gravity.NewWisdomHolman(c)
+		system, _ := gravity.NewSystemWithIntegrator(integrator)
+		if err := system.SetUnits(units); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		sun, _ := gravity.NewBody("Sun", 1, 0, 0, 0)
+		sun.SetPinned(pinned)
+		newPlanet := func(name string) gravity.Body {
+			if pinned {
+				return gravity.NewTestParticle(name, 0, 0, 0)
+			}
+			b, _ := gravity.NewBody(name, 1e-3, 0, 0, 0)
+			return b
+		}
+		planet := newPlanet("Planet")
+		system.AddBody(sun)
+		system.AddBody(planet)
+		if err := system.SetElements(planet, sun, el); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		system.Recenter()
+
+		mu := units.G() * (sun.GetMass() + planet.GetMass())
+		a := math.Abs(el.SemiMajorAxis)
+		motion := math.Sqrt(mu / (a * a * a))
+		dt := 2 * math.Pi / motion / 10
+		steps := 1000
+		if el.Eccentricity > 1 {
+			steps = 100
+		}
+		for i := 0; i < steps; i++ {
+			if err := system.Step(dt); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		expected := el
+		expected.MeanAnomaly += motion * dt * float64(steps)
+		reference := newPlanet("Reference")
+		system.SetElements(reference, sun, expected)
+		return planet.GetPosition().Diff(reference.GetPosition()).Magnitude() / a
+	}
+
+	// two bodies are a pure Kepler problem in Jacobi coordinates, and so is
+	// a test particle around a pinned body in either; democratic
+	// heliocentric coordinates split the motion of a free Sun off
+	t.Run("two bodies", func(t *testing.T) {
+		tests := []struct {
+			name        string
+			coordinates gravity.Coordinates
+			units       gravity.Units
+			el          gravity.Elements
+			pinned      bool
+		}{
+			{"circular", gravity.Jacobi, gravity.Astronomical, gravity.Elements{SemiMajorAxis: 1, MeanAnomaly: 1}, false},
+			{"eccentric", gravity.Jacobi, gravity.Astronomical, gravity.Elements{SemiMajorAxis: 1, Eccentricity: 0.9, Inclination: 0.3, ArgumentOfPeriapsis: 1}, false},
+			{"hyperbolic", gravity.Jacobi, gravity.Astronomical, gravity.Elements{SemiMajorAxis: -1, Eccentricity: 1.5, MeanAnomaly: -20}, false},
+			{"SI", gravity.Jacobi, gravity.SI, gravity.Elements{SemiMajorAxis: 1e+4, Eccentricity: 0.2}, false},
+			{"pinned", gravity.Jacobi, gravity.Astronomical, gravity.Elements{SemiMajorAxis: 1, Eccentricity: 0.5, MeanAnomaly: 2}, true},
+			{"pinned", gravity.DemocraticHeliocentric, gravity.Astronomical, gravity.Elements{SemiMajorAxis: 1, Eccentricity: 0.5, MeanAnomaly: 2}, true},
+			{"pinned flyby", gravity.DemocraticHeliocentric, gravity.Astronomical, gravity.Elements{SemiMajorAxis: -1, Eccentricity: 3, MeanAnomaly: -10}, true},
+		}
+		for _, test := range tests {
+			if got := twoBody(t, test.coordinates, test.units, test.el, test.pinned); got > 1e-8 {
+				t.Fatalf("[%v %v] expected relative error under 1e-8, got %v", test.coordinates, test.name, got)
+			}
+		}
+	})
+
+	// planets builds a Sun with Jupiter and Saturn on eccentric orbits and
+	// returns the largest relative energy error over 100 Jupiter years,
+	// stepped at a twentieth of that year
+	planets := func(integrator gravity.Integrator) float64 {
+		system, _ := gravity.NewSystemWithIntegrator(integrator)
+		system.SetUnits(gravity.Astronomical)
+		sun, _ := gravity.NewBody("Sun", 1, 0, 0, 0)
+		jupiter, _ := gravity.NewBody("Jupiter", 9.55e-4, 0, 0, 0)
+		saturn, _ := gravity.NewBody("Saturn", 2.86e-4, 0, 0, 0)
+		system.AddBody(sun)
+		system.AddBody(jupiter)
+		system.AddBody(saturn)
+		system.SetElements(jupiter, sun, gravity.Elements{SemiMajorAxis: 5.2, Eccentricity: 0.05})
+		system.SetElements(saturn, sun, gravity.Elements{SemiMajorAxis: 9.5, Eccentricity: 0.06, Inclination: 0.04, MeanAnomaly: 2})
+		system.Recenter()
+		system.ResetBaseline()
+
+		year, _ := system.OrbitalPeriod(jupiter, sun)
+		var worst float64
+		for i := 0; i < 2000; i++ {
+			system.Step(year / 20)
+			worst = math.Max(worst, math.Abs(system.GetDrift().Energy))
+		}
+		return worst
+	}
+
+	t.Run("planets", func(t *testing.T) {
+		leapfrog := planets(gravity.NewLeapfrog())
+		for _, c := range coordinates {
+			integrator, _ := gravity.NewWisdomHolman(c)
+			got := planets(integrator)
+			if got > 1e-5 || got > leapfrog/100 {
+				t.Fatalf("[%v] expected energy error under 1e-5 and %v, got %v", c, leapfrog/100, got)
+			}
+		}
+	})
+
+	t.Run("test particles and pinned perturbers", func(t *testing.T) {
+		for _, c := range coordinates {
+			integrator, _ := gravity.NewWisdomHolman(c)
+			system, _ := gravity.NewSystemWithIntegrator(integrator)
+			system.SetUnits(gravity.Astronomical)
+			sun, _ := gravity.NewBody("Sun", 1, 0, 0, 0)
+			rock, _ := gravity.NewBody("Rock", 1e-6, 30, 30, 0)
+			rock.SetPinned(true)
+			dust := gravity.NewTestParticle("Dust", 0, 0, 0)
+			system.AddBody(sun)
+			system.AddBody(rock)
+			system.AddBody(dust)
+			system.SetElements(dust, sun, gravity.Elements{SemiMajorAxis: 1})
+
+			for i := 0; i < 100; i++ {
+				if err := system.Step(10); err != nil {
+					t.Fatalf("[%v] unexpected error: %v", c, err)
+				}
+			}
+			if got := rock.GetPosition(); got.GetX() != 30 || got.GetY() != 30 {
+				t.Fatalf("[%v] expected pinned body at (30, 30), got %v", c, got)
+			}
+			el, _ := system.GetElements(dust, sun)
+			if math.Abs(el.SemiMajorAxis-1) > 1e-6 || el.Eccentricity > 1e-5 {
+				t.Fatalf("[%v] expected a near circular orbit at 1 AU, got %+v", c, el)
+			}
+		}
+	})
+
+	t.Run("gravity other than Newtonian", func(t *testing.T) {
+		mond, _ := gravity.NewMOND(1e-10)
+		tests := []struct {
+			name  string
+			setup func(gravity.System, gravity.Body)
+			reset func(gravity.System)
+		}{
+			{
+				"softening",
+				func(s gravity.System, _ gravity.Body) { s.SetSoftening(1e-3) },
+				func(s gravity.System) { s.SetSoftening(0) },
+			},
+			{
+				"pair force",
+				func(s gravity.System, _ gravity.Body) { s.SetPairForce(mond) },
+				func(s gravity.System) { s.SetPairForce(gravity.NewNewtonian()) },
+			},
+			{
+				"relativity",
+				func(s gravity.System, sun gravity.Body) { s.SetPostNewtonian(sun) },
+				func(s gravity.System) { s.SetPostNewtonian(nil) },
+			},
+		}
+		for _, test := range tests {
+			integrator, _ := gravity.NewWisdomHolman(gravity.Jacobi)
+			system, _ := gravity.NewSystemWithIntegrator(gravity.NewStepDoubling(integrator))
+			system.SetUnits(gravity.Astronomical)
+			sun, _ := gravity.NewBody("Sun", 1, 0, 0, 0)
+			planet, _ := gravity.NewBody("Planet", 1e-3, 0, 0, 0)
+			system.AddBody(sun)
+			system.AddBody(planet)
+			system.SetElements(planet, sun, gravity.Elements{SemiMajorAxis: 1})
+			test.setup(system, sun)
+
+			if err := system.Step(1); err == nil {
+				t.Fatalf("[%v] expected Step error", test.name)
+			}
+			if _, err := system.Advance(1, 1e-6); err == nil {
+				t.Fatalf("[%v] expected Advance error", test.name)
+			}
+			if got := system.GetTime(); got != 0 {
+				t.Fatalf("[%v] expected no time elapsed, got %v", test.name, got)
+			}
+
+			test.reset(system)
+			if err := system.Step(1); err != nil {
+				t.Fatalf("[%v] unexpected error once reset: %v", test.name, err)
+			}
+		}
+	})
+
+	t.Run("#Advance", func(t *testing.T) {
+		integrator, _ := gravity.NewWisdomHolman(gravity.DemocraticHeliocentric)
+		system, _ := gravity.NewSystemWithIntegrator(integrator)
+		system.SetUnits(gravity.Astronomical)
+		sun, _ := gravity.NewBody("Sun", 1, 0, 0, 0)
+		planet, _ := gravity.NewBody("Planet", 1e-3, 0, 0, 0)
+		system.AddBody(sun)
+		system.AddBody(planet)
+		system.SetElements(planet, sun, gravity.Elements{SemiMajorAxis: 1, Eccentricity: 0.3})
+
+		year, _ := system.OrbitalPeriod(planet, sun)
+		if _, err := system.Advance(year, 1e-9); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		el, _ := system.GetElements(planet, sun)
+		if math.Abs(el.SemiMajorAxis-1) > 1e-9 || math.Abs(el.Eccentricity-0.3) > 1e-9 {
+			t.Fatalf("[System.Advance] expected a=1 and e=0.3, got %+v", el)
+		}
+	})
+}